@@ -0,0 +1,113 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+// xapp-lint checks xApp descriptors before deployment:
+//
+//	xapp-lint [-format text|json] [-schema schema.json] [-strict] config-file.json...
+//
+// The schema defaults to schema.json next to each descriptor, if it exists. The
+// exit status is 1 if an error was found (or a warning with -strict), 2 on usage errors.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/descriptor"
+)
+
+func main() {
+	format := flag.String("format", "text", "Output format, text or json.")
+	schemaFile := flag.String("schema", "", "Schema of the controls section, default schema.json next to the descriptor.")
+	strict := flag.Bool("strict", false, "Fail on warnings too.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] descriptor...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 || (*format != "text" && *format != "json") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var results []*descriptor.Result
+	failed := false
+	for _, file := range flag.Args() {
+		res := lint(file, *schemaFile)
+		results = append(results, res)
+		if res.Errors > 0 || (*strict && res.Warnings > 0) {
+			failed = true
+		}
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(results)
+	} else {
+		for _, res := range results {
+			for _, f := range res.Findings {
+				fmt.Printf("%s: %s: [%s] %s: %s\n", res.File, f.Severity, f.Check, f.Path, f.Message)
+			}
+			fmt.Printf("%s: %d error(s), %d warning(s)\n", res.File, res.Errors, res.Warnings)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func lint(file, schemaFile string) *descriptor.Result {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return failure(file, descriptor.CheckSyntax, err)
+	}
+
+	if schemaFile == "" {
+		if f := filepath.Join(filepath.Dir(file), "schema.json"); fileExists(f) {
+			schemaFile = f
+		}
+	}
+	var opts descriptor.Options
+	if schemaFile != "" {
+		if opts.Schema, err = ioutil.ReadFile(schemaFile); err != nil {
+			return failure(file, descriptor.CheckSchema, err)
+		}
+	}
+	return descriptor.Lint(file, data, opts)
+}
+
+func failure(file, check string, err error) *descriptor.Result {
+	return &descriptor.Result{
+		File:     file,
+		Findings: []descriptor.Finding{{Severity: descriptor.SeverityError, Check: check, Message: err.Error()}},
+		Errors:   1,
+	}
+}
+
+func fileExists(f string) bool {
+	_, err := os.Stat(f)
+	return err == nil
+}
@@ -33,6 +33,8 @@ type ClientService interface {
 
 	GetAllSubscriptions(params *GetAllSubscriptionsParams) (*GetAllSubscriptionsOK, error)
 
+	ModifySubscription(params *ModifySubscriptionParams) (*ModifySubscriptionOK, error)
+
 	SetTransport(transport runtime.ClientTransport)
 }
 
@@ -138,6 +140,40 @@ func (a *Client) GetAllSubscriptions(params *GetAllSubscriptionsParams) (*GetAll
 	panic(msg)
 }
 
+/*
+  ModifySubscription modifies the actions or event triggers of an existing subscription
+*/
+func (a *Client) ModifySubscription(params *ModifySubscriptionParams) (*ModifySubscriptionOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewModifySubscriptionParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "ModifySubscription",
+		Method:             "PUT",
+		PathPattern:        "/subscriptions/{subscriptionId}",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &ModifySubscriptionReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*ModifySubscriptionOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for ModifySubscription: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
 // SetTransport changes the transport on the client
 func (a *Client) SetTransport(transport runtime.ClientTransport) {
 	a.transport = transport
@@ -14,6 +14,7 @@ import (
 	"github.com/go-openapi/runtime"
 	cr "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
 )
 
 // NewGetAllSubscriptionsParams creates a new GetAllSubscriptionsParams object
@@ -59,6 +60,38 @@ func NewGetAllSubscriptionsParamsWithHTTPClient(client *http.Client) *GetAllSubs
 for the get all subscriptions operation typically these are written to a http.Request
 */
 type GetAllSubscriptionsParams struct {
+
+	/*ClientEndpoint
+	  Return only the subscriptions having this client endpoint (host:port)
+
+	*/
+	ClientEndpoint *string
+	/*Limit
+	  Maximum number of subscriptions to return
+
+	*/
+	Limit *int64
+	/*Meid
+	  Return only the subscriptions of this MEID
+
+	*/
+	Meid *string
+	/*Offset
+	  Number of matching subscriptions to skip
+
+	*/
+	Offset *int64
+	/*RanFunctionID
+	  Return only the subscriptions of this RAN function
+
+	*/
+	RanFunctionID *int64
+	/*SubscriptionIDPrefix
+	  Return only the subscriptions whose ID starts with this prefix
+
+	*/
+	SubscriptionIDPrefix *string
+
 	timeout    time.Duration
 	Context    context.Context
 	HTTPClient *http.Client
@@ -97,6 +130,72 @@ func (o *GetAllSubscriptionsParams) SetHTTPClient(client *http.Client) {
 	o.HTTPClient = client
 }
 
+// WithClientEndpoint adds the clientEndpoint to the get all subscriptions params
+func (o *GetAllSubscriptionsParams) WithClientEndpoint(clientEndpoint *string) *GetAllSubscriptionsParams {
+	o.SetClientEndpoint(clientEndpoint)
+	return o
+}
+
+// SetClientEndpoint adds the clientEndpoint to the get all subscriptions params
+func (o *GetAllSubscriptionsParams) SetClientEndpoint(clientEndpoint *string) {
+	o.ClientEndpoint = clientEndpoint
+}
+
+// WithLimit adds the limit to the get all subscriptions params
+func (o *GetAllSubscriptionsParams) WithLimit(limit *int64) *GetAllSubscriptionsParams {
+	o.SetLimit(limit)
+	return o
+}
+
+// SetLimit adds the limit to the get all subscriptions params
+func (o *GetAllSubscriptionsParams) SetLimit(limit *int64) {
+	o.Limit = limit
+}
+
+// WithMeid adds the meid to the get all subscriptions params
+func (o *GetAllSubscriptionsParams) WithMeid(meid *string) *GetAllSubscriptionsParams {
+	o.SetMeid(meid)
+	return o
+}
+
+// SetMeid adds the meid to the get all subscriptions params
+func (o *GetAllSubscriptionsParams) SetMeid(meid *string) {
+	o.Meid = meid
+}
+
+// WithOffset adds the offset to the get all subscriptions params
+func (o *GetAllSubscriptionsParams) WithOffset(offset *int64) *GetAllSubscriptionsParams {
+	o.SetOffset(offset)
+	return o
+}
+
+// SetOffset adds the offset to the get all subscriptions params
+func (o *GetAllSubscriptionsParams) SetOffset(offset *int64) {
+	o.Offset = offset
+}
+
+// WithRanFunctionID adds the ranFunctionID to the get all subscriptions params
+func (o *GetAllSubscriptionsParams) WithRanFunctionID(ranFunctionID *int64) *GetAllSubscriptionsParams {
+	o.SetRanFunctionID(ranFunctionID)
+	return o
+}
+
+// SetRanFunctionID adds the ranFunctionId to the get all subscriptions params
+func (o *GetAllSubscriptionsParams) SetRanFunctionID(ranFunctionID *int64) {
+	o.RanFunctionID = ranFunctionID
+}
+
+// WithSubscriptionIDPrefix adds the subscriptionIDPrefix to the get all subscriptions params
+func (o *GetAllSubscriptionsParams) WithSubscriptionIDPrefix(subscriptionIDPrefix *string) *GetAllSubscriptionsParams {
+	o.SetSubscriptionIDPrefix(subscriptionIDPrefix)
+	return o
+}
+
+// SetSubscriptionIDPrefix adds the subscriptionIdPrefix to the get all subscriptions params
+func (o *GetAllSubscriptionsParams) SetSubscriptionIDPrefix(subscriptionIDPrefix *string) {
+	o.SubscriptionIDPrefix = subscriptionIDPrefix
+}
+
 // WriteToRequest writes these params to a swagger request
 func (o *GetAllSubscriptionsParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
 
@@ -105,6 +204,102 @@ func (o *GetAllSubscriptionsParams) WriteToRequest(r runtime.ClientRequest, reg
 	}
 	var res []error
 
+	if o.ClientEndpoint != nil {
+
+		// query param clientEndpoint
+		var qrClientEndpoint string
+		if o.ClientEndpoint != nil {
+			qrClientEndpoint = *o.ClientEndpoint
+		}
+		qClientEndpoint := qrClientEndpoint
+		if qClientEndpoint != "" {
+			if err := r.SetQueryParam("clientEndpoint", qClientEndpoint); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	if o.Limit != nil {
+
+		// query param limit
+		var qrLimit int64
+		if o.Limit != nil {
+			qrLimit = *o.Limit
+		}
+		qLimit := swag.FormatInt64(qrLimit)
+		if qLimit != "" {
+			if err := r.SetQueryParam("limit", qLimit); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	if o.Meid != nil {
+
+		// query param meid
+		var qrMeid string
+		if o.Meid != nil {
+			qrMeid = *o.Meid
+		}
+		qMeid := qrMeid
+		if qMeid != "" {
+			if err := r.SetQueryParam("meid", qMeid); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	if o.Offset != nil {
+
+		// query param offset
+		var qrOffset int64
+		if o.Offset != nil {
+			qrOffset = *o.Offset
+		}
+		qOffset := swag.FormatInt64(qrOffset)
+		if qOffset != "" {
+			if err := r.SetQueryParam("offset", qOffset); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	if o.RanFunctionID != nil {
+
+		// query param ranFunctionId
+		var qrRanFunctionID int64
+		if o.RanFunctionID != nil {
+			qrRanFunctionID = *o.RanFunctionID
+		}
+		qRanFunctionID := swag.FormatInt64(qrRanFunctionID)
+		if qRanFunctionID != "" {
+			if err := r.SetQueryParam("ranFunctionId", qRanFunctionID); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	if o.SubscriptionIDPrefix != nil {
+
+		// query param subscriptionIdPrefix
+		var qrSubscriptionIDPrefix string
+		if o.SubscriptionIDPrefix != nil {
+			qrSubscriptionIDPrefix = *o.SubscriptionIDPrefix
+		}
+		qSubscriptionIDPrefix := qrSubscriptionIDPrefix
+		if qSubscriptionIDPrefix != "" {
+			if err := r.SetQueryParam("subscriptionIdPrefix", qSubscriptionIDPrefix); err != nil {
+				return err
+			}
+		}
+
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
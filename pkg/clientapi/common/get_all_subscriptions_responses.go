@@ -9,8 +9,10 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/go-openapi/errors"
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
 
 	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientmodel"
 )
@@ -51,6 +53,10 @@ func NewGetAllSubscriptionsOK() *GetAllSubscriptionsOK {
 successful query of subscriptions
 */
 type GetAllSubscriptionsOK struct {
+	/*Number of subscriptions matching the filters before pagination
+	 */
+	XTotalCount int64
+
 	Payload clientmodel.SubscriptionList
 }
 
@@ -64,6 +70,17 @@ func (o *GetAllSubscriptionsOK) GetPayload() clientmodel.SubscriptionList {
 
 func (o *GetAllSubscriptionsOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
 
+	// hydrates response header X-Total-Count
+	hdrXTotalCount := response.GetHeader("X-Total-Count")
+
+	if hdrXTotalCount != "" {
+		valxTotalCount, err := swag.ConvertInt64(hdrXTotalCount)
+		if err != nil {
+			return errors.InvalidType("X-Total-Count", "header", "int64", hdrXTotalCount)
+		}
+		o.XTotalCount = valxTotalCount
+	}
+
 	// response payload
 	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
 		return err
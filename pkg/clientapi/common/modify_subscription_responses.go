@@ -0,0 +1,177 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package common
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientmodel"
+)
+
+// ModifySubscriptionReader is a Reader for the ModifySubscription structure.
+type ModifySubscriptionReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *ModifySubscriptionReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewModifySubscriptionOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case 400:
+		result := NewModifySubscriptionBadRequest()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	case 404:
+		result := NewModifySubscriptionNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	case 500:
+		result := NewModifySubscriptionInternalServerError()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+	case 503:
+		result := NewModifySubscriptionServiceUnavailable()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+
+	default:
+		return nil, runtime.NewAPIError("unknown error", response, response.Code())
+	}
+}
+
+// NewModifySubscriptionOK creates a ModifySubscriptionOK with default headers values
+func NewModifySubscriptionOK() *ModifySubscriptionOK {
+	return &ModifySubscriptionOK{}
+}
+
+/*ModifySubscriptionOK handles this case with default header values.
+
+Subscription modification accepted
+*/
+type ModifySubscriptionOK struct {
+	Payload *clientmodel.SubscriptionResponse
+}
+
+func (o *ModifySubscriptionOK) Error() string {
+	return fmt.Sprintf("[PUT /subscriptions/{subscriptionId}][%d] modifySubscriptionOK  %+v", 200, o.Payload)
+}
+
+func (o *ModifySubscriptionOK) GetPayload() *clientmodel.SubscriptionResponse {
+	return o.Payload
+}
+
+func (o *ModifySubscriptionOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(clientmodel.SubscriptionResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewModifySubscriptionBadRequest creates a ModifySubscriptionBadRequest with default headers values
+func NewModifySubscriptionBadRequest() *ModifySubscriptionBadRequest {
+	return &ModifySubscriptionBadRequest{}
+}
+
+/*ModifySubscriptionBadRequest handles this case with default header values.
+
+Invalid input
+*/
+type ModifySubscriptionBadRequest struct {
+}
+
+func (o *ModifySubscriptionBadRequest) Error() string {
+	return fmt.Sprintf("[PUT /subscriptions/{subscriptionId}][%d] modifySubscriptionBadRequest ", 400)
+}
+
+func (o *ModifySubscriptionBadRequest) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
+
+// NewModifySubscriptionNotFound creates a ModifySubscriptionNotFound with default headers values
+func NewModifySubscriptionNotFound() *ModifySubscriptionNotFound {
+	return &ModifySubscriptionNotFound{}
+}
+
+/*ModifySubscriptionNotFound handles this case with default header values.
+
+Subscription not found
+*/
+type ModifySubscriptionNotFound struct {
+}
+
+func (o *ModifySubscriptionNotFound) Error() string {
+	return fmt.Sprintf("[PUT /subscriptions/{subscriptionId}][%d] modifySubscriptionNotFound ", 404)
+}
+
+func (o *ModifySubscriptionNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
+
+// NewModifySubscriptionInternalServerError creates a ModifySubscriptionInternalServerError with default headers values
+func NewModifySubscriptionInternalServerError() *ModifySubscriptionInternalServerError {
+	return &ModifySubscriptionInternalServerError{}
+}
+
+/*ModifySubscriptionInternalServerError handles this case with default header values.
+
+Internal error
+*/
+type ModifySubscriptionInternalServerError struct {
+}
+
+func (o *ModifySubscriptionInternalServerError) Error() string {
+	return fmt.Sprintf("[PUT /subscriptions/{subscriptionId}][%d] modifySubscriptionInternalServerError ", 500)
+}
+
+func (o *ModifySubscriptionInternalServerError) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
+
+// NewModifySubscriptionServiceUnavailable creates a ModifySubscriptionServiceUnavailable with default headers values
+func NewModifySubscriptionServiceUnavailable() *ModifySubscriptionServiceUnavailable {
+	return &ModifySubscriptionServiceUnavailable{}
+}
+
+/*ModifySubscriptionServiceUnavailable handles this case with default header values.
+
+Service Unavailable
+*/
+type ModifySubscriptionServiceUnavailable struct {
+}
+
+func (o *ModifySubscriptionServiceUnavailable) Error() string {
+	return fmt.Sprintf("[PUT /subscriptions/{subscriptionId}][%d] modifySubscriptionServiceUnavailable ", 503)
+}
+
+func (o *ModifySubscriptionServiceUnavailable) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	return nil
+}
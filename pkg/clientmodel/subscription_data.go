@@ -24,6 +24,9 @@ type SubscriptionData struct {
 	// meid
 	Meid string `json:"Meid,omitempty"`
 
+	// r a n function ID
+	RANFunctionID *int64 `json:"RANFunctionID,omitempty"`
+
 	// subscription Id
 	SubscriptionID int64 `json:"SubscriptionId,omitempty"`
 
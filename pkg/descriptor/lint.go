@@ -0,0 +1,340 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+// Package descriptor checks an xApp descriptor, e.g. config/config-file.json,
+// before it is deployed.
+package descriptor
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+)
+
+// The health probe URLs served by the framework, the same as xapp.ReadyURL and xapp.AliveURL
+const (
+	ReadyURL = "/ric/v1/health/ready"
+	AliveURL = "/ric/v1/health/alive"
+)
+
+// SchemaSection is the part of the descriptor described by the schema, the same as xapp.ConfigSchemaSection
+const SchemaSection = "controls"
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Names of the checks reported in Finding.Check
+const (
+	CheckSyntax       = "syntax"
+	CheckSchema       = "schema"
+	CheckPorts        = "ports"
+	CheckMTypes       = "mtypes"
+	CheckMessages     = "messages"
+	CheckProbes       = "probes"
+	CheckMeasurements = "measurements"
+)
+
+// Finding is one problem found in a descriptor
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Check    string   `json:"check"`
+	Path     string   `json:"path,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// Result is the outcome of linting one descriptor
+type Result struct {
+	File     string    `json:"file"`
+	Findings []Finding `json:"findings"`
+	Errors   int       `json:"errors"`
+	Warnings int       `json:"warnings"`
+}
+
+// Options of Lint. A nil MessageTypes means RICMessageTypes, a nil Schema skips the schema validation
+type Options struct {
+	Schema       []byte
+	MessageTypes map[string]int
+}
+
+type linter struct {
+	opts   Options
+	result *Result
+}
+
+// Lint checks the descriptor data of the file
+func Lint(file string, data []byte, opts Options) *Result {
+	if opts.MessageTypes == nil {
+		opts.MessageTypes = RICMessageTypes
+	}
+	l := &linter{opts: opts, result: &Result{File: file, Findings: []Finding{}}}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		l.errorf(CheckSyntax, "", "invalid JSON: %v", err)
+		return l.result
+	}
+
+	l.checkSchema(doc)
+	ports := l.checkPorts(doc)
+	known := l.checkMTypes(doc)
+	l.checkMessages(ports, known)
+	l.checkProbes(doc)
+	l.checkMeasurements(doc)
+
+	return l.result
+}
+
+func (l *linter) add(severity Severity, check, path, format string, args ...interface{}) {
+	l.result.Findings = append(l.result.Findings, Finding{severity, check, path, fmt.Sprintf(format, args...)})
+	if severity == SeverityError {
+		l.result.Errors++
+	} else {
+		l.result.Warnings++
+	}
+}
+
+func (l *linter) errorf(check, path, format string, args ...interface{}) {
+	l.add(SeverityError, check, path, format, args...)
+}
+
+func (l *linter) warnf(check, path, format string, args ...interface{}) {
+	l.add(SeverityWarning, check, path, format, args...)
+}
+
+func (l *linter) checkSchema(doc map[string]interface{}) {
+	for _, k := range []string{"name", "version"} {
+		if s, ok := doc[k].(string); !ok || s == "" {
+			l.errorf(CheckSchema, k, "missing or empty '%s'", k)
+		}
+	}
+	if l.opts.Schema == nil {
+		return
+	}
+
+	schema := &spec.Schema{}
+	if err := json.Unmarshal(l.opts.Schema, schema); err != nil {
+		l.errorf(CheckSchema, "", "invalid schema: %v", err)
+		return
+	}
+	data := doc[SchemaSection]
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	err := validate.AgainstSchema(schema, data, strfmt.Default)
+	if err == nil {
+		return
+	}
+	if c, ok := err.(*errors.CompositeError); ok {
+		for _, e := range c.Errors {
+			l.errorf(CheckSchema, SchemaSection, "%v", e)
+		}
+		return
+	}
+	l.errorf(CheckSchema, SchemaSection, "%v", err)
+}
+
+// checkPorts returns the ports by their path
+func (l *linter) checkPorts(doc map[string]interface{}) map[string]map[string]interface{} {
+	ports := make(map[string]map[string]interface{})
+	messaging, _ := doc["messaging"].(map[string]interface{})
+	list, _ := messaging["ports"].([]interface{})
+
+	names := make(map[string]string)
+	numbers := make(map[string]string)
+	for i, e := range list {
+		path := fmt.Sprintf("messaging.ports[%d]", i)
+		p, ok := e.(map[string]interface{})
+		if !ok {
+			l.errorf(CheckPorts, path, "port is not an object")
+			continue
+		}
+		ports[path] = p
+
+		name, _ := p["name"].(string)
+		if name == "" {
+			l.errorf(CheckPorts, path+".name", "missing port name")
+		} else if prev, ok := names[name]; ok {
+			l.errorf(CheckPorts, path+".name", "duplicate port name '%s', also in %s", name, prev)
+		} else {
+			names[name] = path
+		}
+
+		n, ok := p["port"].(float64)
+		if !ok || n != float64(int(n)) || n < 1 || n > 65535 {
+			l.errorf(CheckPorts, path+".port", "invalid port number %v", p["port"])
+			continue
+		}
+		container, _ := p["container"].(string)
+		key := fmt.Sprintf("%s/%d", container, int(n))
+		if prev, ok := numbers[key]; ok {
+			l.errorf(CheckPorts, path+".port", "port %d conflicts with %s", int(n), prev)
+		} else {
+			numbers[key] = path
+		}
+	}
+	return ports
+}
+
+// checkMTypes checks messaging.mtypes the way LoadConfig adds them, and returns
+// the message types known with them
+func (l *linter) checkMTypes(doc map[string]interface{}) map[string]int {
+	known := make(map[string]int, len(l.opts.MessageTypes))
+	names := make(map[int]string, len(l.opts.MessageTypes))
+	for name, id := range l.opts.MessageTypes {
+		known[name] = id
+		names[id] = name
+	}
+
+	messaging, _ := doc["messaging"].(map[string]interface{})
+	list, _ := messaging["mtypes"].([]interface{})
+	for i, e := range list {
+		path := fmt.Sprintf("messaging.mtypes[%d]", i)
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			l.errorf(CheckMTypes, path, "mtype is not an object")
+			continue
+		}
+		// The fields are matched case-insensitively like viper.UnmarshalKey does
+		var name string
+		var id float64
+		var hasId bool
+		for k, v := range m {
+			switch strings.ToLower(k) {
+			case "name":
+				name, _ = v.(string)
+			case "id":
+				id, hasId = v.(float64)
+			}
+		}
+		if name == "" || !hasId || id != float64(int(id)) {
+			l.errorf(CheckMTypes, path, "mtype needs a Name and an integer Id")
+			continue
+		}
+
+		knownId, nameExists := known[name]
+		knownName, idExists := names[int(id)]
+		switch {
+		case nameExists && idExists && knownId == int(id):
+			l.warnf(CheckMTypes, path, "mtype %s(%d) is already known", name, int(id))
+		case nameExists:
+			l.errorf(CheckMTypes, path, "mtype %s(%d) conflicts with %s(%d), it is skipped at runtime", name, int(id), name, knownId)
+		case idExists:
+			l.errorf(CheckMTypes, path, "mtype %s(%d) conflicts with %s(%d), it is skipped at runtime", name, int(id), knownName, int(id))
+		default:
+			known[name] = int(id)
+			names[int(id)] = name
+		}
+	}
+	return known
+}
+
+func (l *linter) checkMessages(ports map[string]map[string]interface{}, known map[string]int) {
+	paths := make([]string, 0, len(ports))
+	for path := range ports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		for _, k := range []string{"rxMessages", "txMessages"} {
+			list, _ := ports[path][k].([]interface{})
+			for i, e := range list {
+				name, _ := e.(string)
+				if _, ok := known[name]; !ok {
+					l.errorf(CheckMessages, fmt.Sprintf("%s.%s[%d]", path, k, i), "unknown message type '%v'", e)
+				}
+			}
+		}
+	}
+}
+
+func (l *linter) checkProbes(doc map[string]interface{}) {
+	probes := []struct{ key, url string }{
+		{"livenessProbe", AliveURL},
+		{"readinessProbe", ReadyURL},
+	}
+	for _, p := range probes {
+		probe, ok := doc[p.key].(map[string]interface{})
+		if !ok {
+			l.warnf(CheckProbes, p.key, "no %s", p.key)
+			continue
+		}
+		httpGet, ok := probe["httpGet"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path := p.key + ".httpGet.path"
+		url, _ := httpGet["path"].(string)
+		if url != "" && !strings.HasPrefix(url, "/") {
+			l.warnf(CheckProbes, path, "path '%s' does not start with '/'", url)
+			url = "/" + url
+		}
+		if url != p.url {
+			l.errorf(CheckProbes, path, "path '%s' is not served by the framework, expected '%s'", httpGet["path"], p.url)
+		}
+	}
+}
+
+// MetricNameRegexp matches a valid Prometheus metric name
+var MetricNameRegexp = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// checkMeasurements checks the metrics the framework registers from measurements
+func (l *linter) checkMeasurements(doc map[string]interface{}) {
+	measurements, _ := doc["measurements"].([]interface{})
+	names := make(map[string]string)
+	for i, m := range measurements {
+		meas, _ := m.(map[string]interface{})
+		metrics, _ := meas["metrics"].([]interface{})
+		for j, mm := range metrics {
+			metric, _ := mm.(map[string]interface{})
+			path := fmt.Sprintf("measurements[%d].metrics[%d]", i, j)
+			name, _ := metric["name"].(string)
+			if !MetricNameRegexp.MatchString(name) {
+				l.errorf(CheckMeasurements, path+".name", "invalid metric name '%s'", name)
+			} else if prev, ok := names[name]; ok {
+				l.errorf(CheckMeasurements, path+".name", "metric '%s' already declared at %s", name, prev)
+			} else {
+				names[name] = path
+			}
+			switch typ, _ := metric["type"].(string); typ {
+			case "counter", "gauge":
+				if _, ok := metric["buckets"]; ok {
+					l.warnf(CheckMeasurements, path+".buckets", "buckets are only used by histograms")
+				}
+			case "histogram":
+			default:
+				l.errorf(CheckMeasurements, path+".type", "unknown metric type '%s', expected counter, gauge or histogram", typ)
+			}
+			if desc, _ := metric["description"].(string); desc == "" {
+				l.warnf(CheckMeasurements, path+".description", "no description")
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package descriptor
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func findings(res *Result, check string) []Finding {
+	var l []Finding
+	for _, f := range res.Findings {
+		if f.Check == check {
+			l = append(l, f)
+		}
+	}
+	return l
+}
+
+func TestLintExampleDescriptor(t *testing.T) {
+	data, err := ioutil.ReadFile("../../examples/config/config-file.json")
+	assert.Nil(t, err)
+	schema, err := ioutil.ReadFile("../../examples/config/schema.json")
+	assert.Nil(t, err)
+
+	res := Lint("config-file.json", data, Options{Schema: schema})
+	assert.Equal(t, 0, res.Errors, "%+v", res.Findings)
+}
+
+func TestLintInvalidJSON(t *testing.T) {
+	res := Lint("bad.json", []byte(`{"name": `), Options{})
+	assert.Equal(t, 1, res.Errors)
+	assert.Equal(t, CheckSyntax, res.Findings[0].Check)
+}
+
+func TestLintSchema(t *testing.T) {
+	schema := []byte(`{"type": "object", "required": ["subscription"], "properties": {"logger": {"properties": {"level": {"maximum": 4}}}}}`)
+	res := Lint("c.json", []byte(`{"version": "1.0", "controls": {"logger": {"level": 5}}}`), Options{Schema: schema})
+
+	l := findings(res, CheckSchema)
+	assert.Equal(t, 3, len(l), "%+v", l)
+	assert.Equal(t, "name", l[0].Path)
+
+	res = Lint("c.json", []byte(`{"name": "x", "version": "1.0"}`), Options{Schema: []byte(`[`)})
+	assert.Equal(t, 1, len(findings(res, CheckSchema)))
+}
+
+func TestLintPorts(t *testing.T) {
+	res := Lint("c.json", []byte(`{"name": "x", "version": "1", "messaging": {"ports": [
+		{"name": "http", "container": "a", "port": 8080},
+		{"name": "http", "container": "a", "port": 8081},
+		{"name": "rmr", "container": "a", "port": 8080},
+		{"name": "other", "container": "b", "port": 8080},
+		{"container": "a", "port": 70000}
+	]}}`), Options{})
+
+	l := findings(res, CheckPorts)
+	assert.Equal(t, 4, len(l), "%+v", l)
+	assert.Equal(t, "messaging.ports[1].name", l[0].Path)
+	assert.Equal(t, "messaging.ports[2].port", l[1].Path)
+	assert.Equal(t, "messaging.ports[4].name", l[2].Path)
+	assert.Equal(t, "messaging.ports[4].port", l[3].Path)
+}
+
+func TestLintMTypesAndMessages(t *testing.T) {
+	types := map[string]int{"RIC_SUB_REQ": 12010, "RIC_SUB_RESP": 12011}
+	res := Lint("c.json", []byte(`{"name": "x", "version": "1", "messaging": {
+		"ports": [{"name": "rmr", "port": 4560, "rxMessages": ["RIC_SUB_RESP", "MY_MSG", "NO_SUCH_MSG"], "txMessages": ["RIC_SUB_REQ", "RIC_SUB_REQ2"]}],
+		"mtypes": [
+			{"Name": "MY_MSG", "Id": 50000},
+			{"name": "RIC_SUB_REQ", "id": 12010},
+			{"Name": "RIC_SUB_RESP", "Id": 1},
+			{"Name": "RIC_SUB_REQ2", "Id": 12010},
+			{"Name": "BROKEN"}
+		]
+	}}`), Options{MessageTypes: types})
+
+	l := findings(res, CheckMTypes)
+	assert.Equal(t, 4, len(l), "%+v", l)
+	assert.Equal(t, SeverityWarning, l[0].Severity)
+	assert.Equal(t, SeverityError, l[1].Severity)
+	assert.Equal(t, "messaging.mtypes[2]", l[1].Path)
+	assert.Equal(t, "messaging.mtypes[3]", l[2].Path)
+
+	l = findings(res, CheckMessages)
+	assert.Equal(t, 2, len(l), "%+v", l)
+	assert.Equal(t, "messaging.ports[0].rxMessages[2]", l[0].Path)
+	assert.Equal(t, "messaging.ports[0].txMessages[1]", l[1].Path)
+}
+
+func TestLintProbes(t *testing.T) {
+	res := Lint("c.json", []byte(`{"name": "x", "version": "1",
+		"livenessProbe": {"httpGet": {"path": "ric/v1/health/alive", "port": 8080}},
+		"readinessProbe": {"httpGet": {"path": "/ric/v1/health/alive", "port": 8080}}
+	}`), Options{})
+
+	l := findings(res, CheckProbes)
+	assert.Equal(t, 2, len(l), "%+v", l)
+	assert.Equal(t, SeverityWarning, l[0].Severity)
+	assert.Equal(t, "readinessProbe.httpGet.path", l[1].Path)
+	assert.Equal(t, SeverityError, l[1].Severity)
+
+	res = Lint("c.json", []byte(`{"name": "x", "version": "1"}`), Options{})
+	assert.Equal(t, 2, res.Warnings)
+	assert.Equal(t, 0, res.Errors)
+}
+
+func TestLintMeasurements(t *testing.T) {
+	res := Lint("c.json", []byte(`{"name": "x", "version": "1", "measurements": [{"moId": "m", "metrics": [
+		{"name": "ricxapp_X_Requests", "type": "counter", "description": "Requests"},
+		{"name": "ricxapp_X_Requests", "type": "gauge", "description": "Requests", "buckets": [1]},
+		{"name": "ricxapp-X-Latency", "type": "summary"}
+	]}]}`), Options{})
+
+	l := findings(res, CheckMeasurements)
+	assert.Equal(t, 5, len(l), "%+v", l)
+	assert.Equal(t, "measurements[0].metrics[1].name", l[0].Path)
+	assert.Equal(t, "measurements[0].metrics[1].buckets", l[1].Path)
+	assert.Equal(t, "measurements[0].metrics[2].name", l[2].Path)
+	assert.Equal(t, "measurements[0].metrics[2].type", l[3].Path)
+	assert.Equal(t, SeverityWarning, l[4].Severity)
+}
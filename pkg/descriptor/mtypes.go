@@ -0,0 +1,32 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package descriptor
+
+// The message type table is generated from the RMR header, rmr-dev must be installed
+//go:generate go run mtypes_gen.go -header /usr/local/include/rmr/RIC_message_types.h -o mtypes_table.go
+
+// MessageTypes returns a copy of RICMessageTypes, xapp.RICMessageTypes is initialized with it
+func MessageTypes() map[string]int {
+	m := make(map[string]int, len(RICMessageTypes))
+	for k, v := range RICMessageTypes {
+		m[k] = v
+	}
+	return m
+}
@@ -0,0 +1,189 @@
+//go:build ignore
+
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+// mtypes_gen generates the message type table of the package from the RMR header
+// RIC_message_types.h, installed by rmr-dev:
+//
+//	go generate ./pkg/descriptor
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+)
+
+// messageTypes are the names in the table, in order, with the header name if it differs
+var messageTypes = []struct{ name, define string }{
+	{"RIC_HEALTH_CHECK_REQ", ""},
+	{"RIC_HEALTH_CHECK_RESP", ""},
+	{"RIC_SCTP_CONNECTION_FAILURE", ""},
+	{"RIC_SCTP_CLEAR_ALL", ""},
+	{"E2_TERM_INIT", ""},
+	{"E2_TERM_KEEP_ALIVE_REQ", ""},
+	{"E2_TERM_KEEP_ALIVE_RESP", ""},
+	{"RAN_CONNECTED", ""},
+	{"RAN_RESTARTED", ""},
+	{"RAN_RECONFIGURED", ""},
+	{"RIC_SUB_REQ", ""},
+	{"RIC_SUB_RESP", ""},
+	{"RIC_SUB_FAILURE", ""},
+	{"RIC_SUB_DEL_REQ", ""},
+	{"RIC_SUB_DEL_RESP", ""},
+	{"RIC_SUB_DEL_FAILURE", ""},
+	{"RIC_SUB_DEL_REQUIRED", ""},
+	{"RIC_SERVICE_UPDATE", ""},
+	{"RIC_SERVICE_UPDATE_ACK", ""},
+	{"RIC_SERVICE_UPDATE_FAILURE", ""},
+	{"RIC_CONTROL_REQ", ""},
+	{"RIC_CONTROL_ACK", ""},
+	{"RIC_CONTROL_FAILURE", ""},
+	{"RIC_INDICATION", ""},
+	{"RIC_SERVICE_QUERY", ""},
+	{"RIC_X2_SETUP_REQ", ""},
+	{"RIC_X2_SETUP_RESP", ""},
+	{"RIC_X2_SETUP_FAILURE", ""},
+	{"RIC_X2_RESET", ""},
+	{"RIC_X2_RESET_RESP", ""},
+	{"RIC_ENDC_X2_SETUP_REQ", ""},
+	{"RIC_ENDC_X2_SETUP_RESP", ""},
+	{"RIC_ENDC_X2_SETUP_FAILURE", ""},
+	{"RIC_ENDC_CONF_UPDATE", ""},
+	{"RIC_ENDC_CONF_UPDATE_ACK", ""},
+	{"RIC_ENDC_CONF_UPDATE_FAILURE", ""},
+	{"RIC_RES_STATUS_REQ", ""},
+	{"RIC_RES_STATUS_RESP", ""},
+	{"RIC_RES_STATUS_FAILURE", ""},
+	{"RIC_ENB_CONF_UPDATE", ""},
+	{"RIC_ENB_CONF_UPDATE_ACK", ""},
+	{"RIC_ENB_CONF_UPDATE_FAILURE", ""},
+	{"RIC_ENB_LOAD_INFORMATION", ""},
+	{"RIC_GNB_STATUS_INDICATION", ""},
+	{"RIC_RESOURCE_STATUS_UPDATE", ""},
+	{"RIC_ERROR_INDICATION", ""},
+	{"RIC_SGNB_ADDITION_REQ", ""},
+	{"RIC_SGNB_ADDITION_ACK", ""},
+	{"RIC_SGNB_ADDITION_REJECT", ""},
+	{"RIC_SGNB_MOD_REQUEST", ""},
+	{"RIC_SGNB_MOD_REQUEST_ACK", ""},
+	{"RIC_SGNB_MOD_REQUEST_REJECT", "RIC_SGNB_MOD_REQUEST_REJ"},
+	{"RIC_SGNB_MOD_REQUIRED", ""},
+	{"RIC_SGNB_MOD_CONFIRM", ""},
+	{"RIC_SGNB_MOD_REFUSE", ""},
+	{"RIC_SGNB_RELEASE_REQUEST", ""},
+	{"RIC_SGNB_RELEASE_CONFIRM", ""},
+	{"RIC_SGNB_RELEASE_REQUIRED", ""},
+	{"RIC_SGNB_RELEASE_REQUEST_ACK", ""},
+	{"RIC_SECONDARY_RAT_DATA_USAGE_REPORT", ""},
+	{"RIC_SN_STATUS_TRANSFER", ""},
+	{"RIC_SGNB_RECONF_COMPLETE", ""},
+	{"RIC_RRC_TRANSFER", ""},
+	{"RIC_UE_CONTEXT_RELEASE", ""},
+	{"DC_ADM_INT_CONTROL", ""},
+	{"DC_ADM_INT_CONTROL_ACK", ""},
+	{"DC_ADM_GET_POLICY", ""},
+	{"DC_ADM_GET_POLICY_ACK", ""},
+	{"A1_POLICY_REQ", ""},
+	{"A1_POLICY_RESP", ""},
+	{"A1_POLICY_QUERY", ""},
+	{"RIC_X2_SETUP", ""},
+	{"RIC_X2_RESPONSE", ""},
+	{"RIC_X2_RESOURCE_STATUS_REQUEST", ""},
+	{"RIC_X2_RESOURCE_STATUS_RESPONSE", ""},
+	{"RIC_X2_LOAD_INFORMATION", ""},
+	{"RIC_E2_TERMINATION_HC_REQUEST", ""},
+	{"RIC_E2_TERMINATION_HC_RESPONSE", ""},
+	{"RIC_E2_MANAGER_HC_REQUEST", ""},
+	{"RIC_E2_MANAGER_HC_RESPONSE", ""},
+	{"RIC_CONTROL_XAPP_CONFIG_REQUEST", ""},
+	{"RIC_CONTROL_XAPP_CONFIG_RESPONSE", ""},
+	{"RMRRM_TABLE_DATA", ""},
+	{"RMRRM_REQ_TABLE", ""},
+	{"RMRRM_TABLE_STATE", ""},
+	{"RIC_E2_SETUP_REQ", ""},
+	{"RIC_E2_SETUP_RESP", ""},
+	{"RIC_E2_SETUP_FAILURE", ""},
+	{"TS_UE_LIST", ""},
+	{"TS_QOE_PRED_REQ", ""},
+	{"TS_QOE_PREDICTION", ""},
+	{"MC_REPORT", ""},
+	{"DCAPTERM_RTPM_RMR_MSGTYPE", ""},
+	{"DCAPTERM_GEO_RMR_MSGTYPE", ""},
+	{"RIC_E2_RAN_ERROR_INDICATION", ""},
+}
+
+var defineRegexp = regexp.MustCompile(`^\s*#\s*define\s+([A-Za-z0-9_]+)\s+(-?[0-9]+)\b`)
+
+func main() {
+	header := flag.String("header", "/usr/local/include/rmr/RIC_message_types.h", "RMR message type header")
+	output := flag.String("o", "mtypes_table.go", "output file")
+	flag.Parse()
+
+	f, err := os.Open(*header)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	defines := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if m := defineRegexp.FindStringSubmatch(scanner.Text()); m != nil {
+			defines[m[1]], _ = strconv.Atoi(m[2])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by mtypes_gen.go from RIC_message_types.h; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package descriptor\n\n")
+	fmt.Fprintf(&b, "// RICMessageTypes are the message types known by RMR. Use MessageTypes for a copy that can be modified\n")
+	fmt.Fprintf(&b, "var RICMessageTypes = map[string]int{\n")
+	for _, t := range messageTypes {
+		define := t.define
+		if define == "" {
+			define = t.name
+		}
+		v, ok := defines[define]
+		if !ok {
+			log.Fatalf("%s not defined in %s", define, *header)
+		}
+		fmt.Fprintf(&b, "\t%q: %d,\n", t.name, v)
+	}
+	fmt.Fprintf(&b, "}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,102 @@
+// Code generated by mtypes_gen.go from RIC_message_types.h; DO NOT EDIT.
+
+package descriptor
+
+// RICMessageTypes are the message types known by RMR. Use MessageTypes for a copy that can be modified
+var RICMessageTypes = map[string]int{
+	"RIC_HEALTH_CHECK_REQ":                100,
+	"RIC_HEALTH_CHECK_RESP":               101,
+	"RIC_SCTP_CONNECTION_FAILURE":         1080,
+	"RIC_SCTP_CLEAR_ALL":                  1090,
+	"E2_TERM_INIT":                        1100,
+	"E2_TERM_KEEP_ALIVE_REQ":              1101,
+	"E2_TERM_KEEP_ALIVE_RESP":             1102,
+	"RAN_CONNECTED":                       1200,
+	"RAN_RESTARTED":                       1210,
+	"RAN_RECONFIGURED":                    1220,
+	"RIC_SUB_REQ":                         12010,
+	"RIC_SUB_RESP":                        12011,
+	"RIC_SUB_FAILURE":                     12012,
+	"RIC_SUB_DEL_REQ":                     12020,
+	"RIC_SUB_DEL_RESP":                    12021,
+	"RIC_SUB_DEL_FAILURE":                 12022,
+	"RIC_SUB_DEL_REQUIRED":                12023,
+	"RIC_SERVICE_UPDATE":                  12030,
+	"RIC_SERVICE_UPDATE_ACK":              12031,
+	"RIC_SERVICE_UPDATE_FAILURE":          12032,
+	"RIC_CONTROL_REQ":                     12040,
+	"RIC_CONTROL_ACK":                     12041,
+	"RIC_CONTROL_FAILURE":                 12042,
+	"RIC_INDICATION":                      12050,
+	"RIC_SERVICE_QUERY":                   12060,
+	"RIC_X2_SETUP_REQ":                    10060,
+	"RIC_X2_SETUP_RESP":                   10061,
+	"RIC_X2_SETUP_FAILURE":                10062,
+	"RIC_X2_RESET":                        10070,
+	"RIC_X2_RESET_RESP":                   10071,
+	"RIC_ENDC_X2_SETUP_REQ":               10360,
+	"RIC_ENDC_X2_SETUP_RESP":              10361,
+	"RIC_ENDC_X2_SETUP_FAILURE":           10362,
+	"RIC_ENDC_CONF_UPDATE":                10370,
+	"RIC_ENDC_CONF_UPDATE_ACK":            10371,
+	"RIC_ENDC_CONF_UPDATE_FAILURE":        10372,
+	"RIC_RES_STATUS_REQ":                  10090,
+	"RIC_RES_STATUS_RESP":                 10091,
+	"RIC_RES_STATUS_FAILURE":              10092,
+	"RIC_ENB_CONF_UPDATE":                 10080,
+	"RIC_ENB_CONF_UPDATE_ACK":             10081,
+	"RIC_ENB_CONF_UPDATE_FAILURE":         10082,
+	"RIC_ENB_LOAD_INFORMATION":            10020,
+	"RIC_GNB_STATUS_INDICATION":           10450,
+	"RIC_RESOURCE_STATUS_UPDATE":          10100,
+	"RIC_ERROR_INDICATION":                10030,
+	"RIC_SGNB_ADDITION_REQ":               10270,
+	"RIC_SGNB_ADDITION_ACK":               10271,
+	"RIC_SGNB_ADDITION_REJECT":            10272,
+	"RIC_SGNB_MOD_REQUEST":                10290,
+	"RIC_SGNB_MOD_REQUEST_ACK":            10291,
+	"RIC_SGNB_MOD_REQUEST_REJECT":         10292,
+	"RIC_SGNB_MOD_REQUIRED":               10300,
+	"RIC_SGNB_MOD_CONFIRM":                10301,
+	"RIC_SGNB_MOD_REFUSE":                 10302,
+	"RIC_SGNB_RELEASE_REQUEST":            10310,
+	"RIC_SGNB_RELEASE_CONFIRM":            10321,
+	"RIC_SGNB_RELEASE_REQUIRED":           10320,
+	"RIC_SGNB_RELEASE_REQUEST_ACK":        10311,
+	"RIC_SECONDARY_RAT_DATA_USAGE_REPORT": 10380,
+	"RIC_SN_STATUS_TRANSFER":              10040,
+	"RIC_SGNB_RECONF_COMPLETE":            10280,
+	"RIC_RRC_TRANSFER":                    10350,
+	"RIC_UE_CONTEXT_RELEASE":              10050,
+	"DC_ADM_INT_CONTROL":                  20000,
+	"DC_ADM_INT_CONTROL_ACK":              20001,
+	"DC_ADM_GET_POLICY":                   20002,
+	"DC_ADM_GET_POLICY_ACK":               20003,
+	"A1_POLICY_REQ":                       20010,
+	"A1_POLICY_RESP":                      20011,
+	"A1_POLICY_QUERY":                     20012,
+	"RIC_X2_SETUP":                        10000,
+	"RIC_X2_RESPONSE":                     10001,
+	"RIC_X2_RESOURCE_STATUS_REQUEST":      10002,
+	"RIC_X2_RESOURCE_STATUS_RESPONSE":     10003,
+	"RIC_X2_LOAD_INFORMATION":             10004,
+	"RIC_E2_TERMINATION_HC_REQUEST":       10005,
+	"RIC_E2_TERMINATION_HC_RESPONSE":      10006,
+	"RIC_E2_MANAGER_HC_REQUEST":           10007,
+	"RIC_E2_MANAGER_HC_RESPONSE":          10008,
+	"RIC_CONTROL_XAPP_CONFIG_REQUEST":     100000,
+	"RIC_CONTROL_XAPP_CONFIG_RESPONSE":    100001,
+	"RMRRM_TABLE_DATA":                    20,
+	"RMRRM_REQ_TABLE":                     21,
+	"RMRRM_TABLE_STATE":                   22,
+	"RIC_E2_SETUP_REQ":                    12001,
+	"RIC_E2_SETUP_RESP":                   12002,
+	"RIC_E2_SETUP_FAILURE":                12003,
+	"TS_UE_LIST":                          30000,
+	"TS_QOE_PRED_REQ":                     30001,
+	"TS_QOE_PREDICTION":                   30002,
+	"MC_REPORT":                           30010,
+	"DCAPTERM_RTPM_RMR_MSGTYPE":           33001,
+	"DCAPTERM_GEO_RMR_MSGTYPE":            33002,
+	"RIC_E2_RAN_ERROR_INDICATION":         12007,
+}
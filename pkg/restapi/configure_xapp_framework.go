@@ -52,6 +52,11 @@ func configureAPI(api *operations.XappFrameworkAPI) http.Handler {
 			return middleware.NotImplemented("operation common.GetAllSubscriptions has not yet been implemented")
 		})
 	}
+	if api.CommonModifySubscriptionHandler == nil {
+		api.CommonModifySubscriptionHandler = common.ModifySubscriptionHandlerFunc(func(params common.ModifySubscriptionParams) middleware.Responder {
+			return middleware.NotImplemented("operation common.ModifySubscription has not yet been implemented")
+		})
+	}
 	if api.XappGetXappConfigListHandler == nil {
 		api.XappGetXappConfigListHandler = xapp.GetXappConfigListHandlerFunc(func(params xapp.GetXappConfigListParams) middleware.Responder {
 			return middleware.NotImplemented("operation xapp.GetXappConfigList has not yet been implemented")
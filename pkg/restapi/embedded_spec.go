@@ -68,11 +68,57 @@ func init() {
         ],
         "summary": "Returns list of subscriptions",
         "operationId": "getAllSubscriptions",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "Return only the subscriptions of this MEID",
+            "name": "meid",
+            "in": "query"
+          },
+          {
+            "type": "integer",
+            "description": "Return only the subscriptions of this RAN function",
+            "name": "ranFunctionId",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "Return only the subscriptions having this client endpoint (host:port)",
+            "name": "clientEndpoint",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "Return only the subscriptions whose ID starts with this prefix",
+            "name": "subscriptionIdPrefix",
+            "in": "query"
+          },
+          {
+            "minimum": 0,
+            "type": "integer",
+            "description": "Number of matching subscriptions to skip",
+            "name": "offset",
+            "in": "query"
+          },
+          {
+            "minimum": 1,
+            "type": "integer",
+            "description": "Maximum number of subscriptions to return",
+            "name": "limit",
+            "in": "query"
+          }
+        ],
         "responses": {
           "200": {
             "description": "successful query of subscriptions",
             "schema": {
               "$ref": "#/definitions/SubscriptionList"
+            },
+            "headers": {
+              "X-Total-Count": {
+                "type": "integer",
+                "description": "Number of subscriptions matching the filters before pagination"
+              }
             }
           },
           "500": {
@@ -151,6 +197,57 @@ func init() {
             "description": "Internal error"
           }
         }
+      },
+      "put": {
+        "consumes": [
+          "application/json"
+        ],
+        "produces": [
+          "application/json"
+        ],
+        "tags": [
+          "common"
+        ],
+        "summary": "Modify the actions or event triggers of an existing subscription",
+        "operationId": "ModifySubscription",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "The subscriptionId received in the Subscription Response",
+            "name": "subscriptionId",
+            "in": "path",
+            "required": true
+          },
+          {
+            "description": "Subscription parameters replacing the current ones",
+            "name": "SubscriptionParams",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/SubscriptionParams"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Subscription modification accepted",
+            "schema": {
+              "$ref": "#/definitions/SubscriptionResponse"
+            }
+          },
+          "400": {
+            "description": "Invalid input"
+          },
+          "404": {
+            "description": "Subscription not found"
+          },
+          "500": {
+            "description": "Internal error"
+          },
+          "503": {
+            "description": "Service Unavailable"
+          }
+        }
       }
     }
   },
@@ -236,6 +333,10 @@ func init() {
         "Meid": {
           "type": "string"
         },
+        "RANFunctionID": {
+          "type": "integer",
+          "x-nullable": true
+        },
         "SubscriptionId": {
           "type": "integer"
         },
@@ -480,11 +581,57 @@ func init() {
         ],
         "summary": "Returns list of subscriptions",
         "operationId": "getAllSubscriptions",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "Return only the subscriptions of this MEID",
+            "name": "meid",
+            "in": "query"
+          },
+          {
+            "type": "integer",
+            "description": "Return only the subscriptions of this RAN function",
+            "name": "ranFunctionId",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "Return only the subscriptions having this client endpoint (host:port)",
+            "name": "clientEndpoint",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "Return only the subscriptions whose ID starts with this prefix",
+            "name": "subscriptionIdPrefix",
+            "in": "query"
+          },
+          {
+            "minimum": 0,
+            "type": "integer",
+            "description": "Number of matching subscriptions to skip",
+            "name": "offset",
+            "in": "query"
+          },
+          {
+            "minimum": 1,
+            "type": "integer",
+            "description": "Maximum number of subscriptions to return",
+            "name": "limit",
+            "in": "query"
+          }
+        ],
         "responses": {
           "200": {
             "description": "successful query of subscriptions",
             "schema": {
               "$ref": "#/definitions/SubscriptionList"
+            },
+            "headers": {
+              "X-Total-Count": {
+                "type": "integer",
+                "description": "Number of subscriptions matching the filters before pagination"
+              }
             }
           },
           "500": {
@@ -563,6 +710,57 @@ func init() {
             "description": "Internal error"
           }
         }
+      },
+      "put": {
+        "consumes": [
+          "application/json"
+        ],
+        "produces": [
+          "application/json"
+        ],
+        "tags": [
+          "common"
+        ],
+        "summary": "Modify the actions or event triggers of an existing subscription",
+        "operationId": "ModifySubscription",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "The subscriptionId received in the Subscription Response",
+            "name": "subscriptionId",
+            "in": "path",
+            "required": true
+          },
+          {
+            "description": "Subscription parameters replacing the current ones",
+            "name": "SubscriptionParams",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/SubscriptionParams"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Subscription modification accepted",
+            "schema": {
+              "$ref": "#/definitions/SubscriptionResponse"
+            }
+          },
+          "400": {
+            "description": "Invalid input"
+          },
+          "404": {
+            "description": "Subscription not found"
+          },
+          "500": {
+            "description": "Internal error"
+          },
+          "503": {
+            "description": "Service Unavailable"
+          }
+        }
       }
     }
   },
@@ -649,6 +847,10 @@ func init() {
         "Meid": {
           "type": "string"
         },
+        "RANFunctionID": {
+          "type": "integer",
+          "x-nullable": true
+        },
         "SubscriptionId": {
           "type": "integer"
         },
@@ -9,7 +9,11 @@ import (
 	"net/http"
 
 	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
 )
 
 // NewGetAllSubscriptionsParams creates a new GetAllSubscriptionsParams object
@@ -27,6 +31,33 @@ type GetAllSubscriptionsParams struct {
 
 	// HTTP Request Object
 	HTTPRequest *http.Request `json:"-"`
+
+	/*Return only the subscriptions having this client endpoint (host:port)
+	  In: query
+	*/
+	ClientEndpoint *string
+	/*Maximum number of subscriptions to return
+	  Minimum: 1
+	  In: query
+	*/
+	Limit *int64
+	/*Return only the subscriptions of this MEID
+	  In: query
+	*/
+	Meid *string
+	/*Number of matching subscriptions to skip
+	  Minimum: 0
+	  In: query
+	*/
+	Offset *int64
+	/*Return only the subscriptions of this RAN function
+	  In: query
+	*/
+	RanFunctionID *int64
+	/*Return only the subscriptions whose ID starts with this prefix
+	  In: query
+	*/
+	SubscriptionIDPrefix *string
 }
 
 // BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
@@ -38,8 +69,188 @@ func (o *GetAllSubscriptionsParams) BindRequest(r *http.Request, route *middlewa
 
 	o.HTTPRequest = r
 
+	qs := runtime.Values(r.URL.Query())
+
+	qClientEndpoint, qhkClientEndpoint, _ := qs.GetOK("clientEndpoint")
+	if err := o.bindClientEndpoint(qClientEndpoint, qhkClientEndpoint, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qLimit, qhkLimit, _ := qs.GetOK("limit")
+	if err := o.bindLimit(qLimit, qhkLimit, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qMeid, qhkMeid, _ := qs.GetOK("meid")
+	if err := o.bindMeid(qMeid, qhkMeid, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qOffset, qhkOffset, _ := qs.GetOK("offset")
+	if err := o.bindOffset(qOffset, qhkOffset, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qRanFunctionID, qhkRanFunctionID, _ := qs.GetOK("ranFunctionId")
+	if err := o.bindRanFunctionID(qRanFunctionID, qhkRanFunctionID, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qSubscriptionIDPrefix, qhkSubscriptionIDPrefix, _ := qs.GetOK("subscriptionIdPrefix")
+	if err := o.bindSubscriptionIDPrefix(qSubscriptionIDPrefix, qhkSubscriptionIDPrefix, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
+
+// bindClientEndpoint binds and validates parameter ClientEndpoint from query.
+func (o *GetAllSubscriptionsParams) bindClientEndpoint(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.ClientEndpoint = &raw
+
+	return nil
+}
+
+// bindLimit binds and validates parameter Limit from query.
+func (o *GetAllSubscriptionsParams) bindLimit(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := swag.ConvertInt64(raw)
+	if err != nil {
+		return errors.InvalidType("limit", "query", "int64", raw)
+	}
+	o.Limit = &value
+
+	if err := o.validateLimit(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateLimit carries on validations for parameter Limit
+func (o *GetAllSubscriptionsParams) validateLimit(formats strfmt.Registry) error {
+
+	if err := validate.MinimumInt("limit", "query", int64(*o.Limit), 1, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bindMeid binds and validates parameter Meid from query.
+func (o *GetAllSubscriptionsParams) bindMeid(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.Meid = &raw
+
+	return nil
+}
+
+// bindOffset binds and validates parameter Offset from query.
+func (o *GetAllSubscriptionsParams) bindOffset(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := swag.ConvertInt64(raw)
+	if err != nil {
+		return errors.InvalidType("offset", "query", "int64", raw)
+	}
+	o.Offset = &value
+
+	if err := o.validateOffset(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateOffset carries on validations for parameter Offset
+func (o *GetAllSubscriptionsParams) validateOffset(formats strfmt.Registry) error {
+
+	if err := validate.MinimumInt("offset", "query", int64(*o.Offset), 0, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bindRanFunctionID binds and validates parameter RanFunctionID from query.
+func (o *GetAllSubscriptionsParams) bindRanFunctionID(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := swag.ConvertInt64(raw)
+	if err != nil {
+		return errors.InvalidType("ranFunctionId", "query", "int64", raw)
+	}
+	o.RanFunctionID = &value
+
+	return nil
+}
+
+// bindSubscriptionIDPrefix binds and validates parameter SubscriptionIDPrefix from query.
+func (o *GetAllSubscriptionsParams) bindSubscriptionIDPrefix(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.SubscriptionIDPrefix = &raw
+
+	return nil
+}
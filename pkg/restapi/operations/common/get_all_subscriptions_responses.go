@@ -9,6 +9,7 @@ import (
 	"net/http"
 
 	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/swag"
 
 	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/models"
 )
@@ -21,6 +22,10 @@ const GetAllSubscriptionsOKCode int = 200
 swagger:response getAllSubscriptionsOK
 */
 type GetAllSubscriptionsOK struct {
+	/*Number of subscriptions matching the filters before pagination
+
+	 */
+	XTotalCount int64 `json:"X-Total-Count"`
 
 	/*
 	  In: Body
@@ -34,6 +39,17 @@ func NewGetAllSubscriptionsOK() *GetAllSubscriptionsOK {
 	return &GetAllSubscriptionsOK{}
 }
 
+// WithXTotalCount adds the xTotalCount to the get all subscriptions o k response
+func (o *GetAllSubscriptionsOK) WithXTotalCount(xTotalCount int64) *GetAllSubscriptionsOK {
+	o.XTotalCount = xTotalCount
+	return o
+}
+
+// SetXTotalCount sets the xTotalCount to the get all subscriptions o k response
+func (o *GetAllSubscriptionsOK) SetXTotalCount(xTotalCount int64) {
+	o.XTotalCount = xTotalCount
+}
+
 // WithPayload adds the payload to the get all subscriptions o k response
 func (o *GetAllSubscriptionsOK) WithPayload(payload models.SubscriptionList) *GetAllSubscriptionsOK {
 	o.Payload = payload
@@ -48,6 +64,13 @@ func (o *GetAllSubscriptionsOK) SetPayload(payload models.SubscriptionList) {
 // WriteResponse to the client
 func (o *GetAllSubscriptionsOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
 
+	// response header X-Total-Count
+
+	xTotalCount := swag.FormatInt64(o.XTotalCount)
+	if xTotalCount != "" {
+		rw.Header().Set("X-Total-Count", xTotalCount)
+	}
+
 	rw.WriteHeader(200)
 	payload := o.Payload
 	if payload == nil {
@@ -9,11 +9,22 @@ import (
 	"errors"
 	"net/url"
 	golangswaggerpaths "path"
+
+	"github.com/go-openapi/swag"
 )
 
 // GetAllSubscriptionsURL generates an URL for the get all subscriptions operation
 type GetAllSubscriptionsURL struct {
+	ClientEndpoint       *string
+	Limit                *int64
+	Meid                 *string
+	Offset               *int64
+	RanFunctionID        *int64
+	SubscriptionIDPrefix *string
+
 	_basePath string
+	// avoid unkeyed usage
+	_ struct{}
 }
 
 // WithBasePath sets the base path for this url builder, only required when it's different from the
@@ -43,6 +54,58 @@ func (o *GetAllSubscriptionsURL) Build() (*url.URL, error) {
 	}
 	_result.Path = golangswaggerpaths.Join(_basePath, _path)
 
+	qs := make(url.Values)
+
+	var clientEndpointQ string
+	if o.ClientEndpoint != nil {
+		clientEndpointQ = *o.ClientEndpoint
+	}
+	if clientEndpointQ != "" {
+		qs.Set("clientEndpoint", clientEndpointQ)
+	}
+
+	var limitQ string
+	if o.Limit != nil {
+		limitQ = swag.FormatInt64(*o.Limit)
+	}
+	if limitQ != "" {
+		qs.Set("limit", limitQ)
+	}
+
+	var meidQ string
+	if o.Meid != nil {
+		meidQ = *o.Meid
+	}
+	if meidQ != "" {
+		qs.Set("meid", meidQ)
+	}
+
+	var offsetQ string
+	if o.Offset != nil {
+		offsetQ = swag.FormatInt64(*o.Offset)
+	}
+	if offsetQ != "" {
+		qs.Set("offset", offsetQ)
+	}
+
+	var ranFunctionIDQ string
+	if o.RanFunctionID != nil {
+		ranFunctionIDQ = swag.FormatInt64(*o.RanFunctionID)
+	}
+	if ranFunctionIDQ != "" {
+		qs.Set("ranFunctionId", ranFunctionIDQ)
+	}
+
+	var subscriptionIDPrefixQ string
+	if o.SubscriptionIDPrefix != nil {
+		subscriptionIDPrefixQ = *o.SubscriptionIDPrefix
+	}
+	if subscriptionIDPrefixQ != "" {
+		qs.Set("subscriptionIdPrefix", subscriptionIDPrefixQ)
+	}
+
+	_result.RawQuery = qs.Encode()
+
 	return &_result, nil
 }
 
@@ -54,6 +54,9 @@ func NewXappFrameworkAPI(spec *loads.Document) *XappFrameworkAPI {
 		CommonGetAllSubscriptionsHandler: common.GetAllSubscriptionsHandlerFunc(func(params common.GetAllSubscriptionsParams) middleware.Responder {
 			return middleware.NotImplemented("operation common.GetAllSubscriptions has not yet been implemented")
 		}),
+		CommonModifySubscriptionHandler: common.ModifySubscriptionHandlerFunc(func(params common.ModifySubscriptionParams) middleware.Responder {
+			return middleware.NotImplemented("operation common.ModifySubscription has not yet been implemented")
+		}),
 		XappGetXappConfigListHandler: xapp.GetXappConfigListHandlerFunc(func(params xapp.GetXappConfigListParams) middleware.Responder {
 			return middleware.NotImplemented("operation xapp.GetXappConfigList has not yet been implemented")
 		}),
@@ -99,6 +102,8 @@ type XappFrameworkAPI struct {
 	CommonUnsubscribeHandler common.UnsubscribeHandler
 	// CommonGetAllSubscriptionsHandler sets the operation handler for the get all subscriptions operation
 	CommonGetAllSubscriptionsHandler common.GetAllSubscriptionsHandler
+	// CommonModifySubscriptionHandler sets the operation handler for the modify subscription operation
+	CommonModifySubscriptionHandler common.ModifySubscriptionHandler
 	// XappGetXappConfigListHandler sets the operation handler for the get xapp config list operation
 	XappGetXappConfigListHandler xapp.GetXappConfigListHandler
 	// ServeError is called when an error is received, there is a default handler
@@ -179,6 +184,9 @@ func (o *XappFrameworkAPI) Validate() error {
 	if o.CommonGetAllSubscriptionsHandler == nil {
 		unregistered = append(unregistered, "common.GetAllSubscriptionsHandler")
 	}
+	if o.CommonModifySubscriptionHandler == nil {
+		unregistered = append(unregistered, "common.ModifySubscriptionHandler")
+	}
 	if o.XappGetXappConfigListHandler == nil {
 		unregistered = append(unregistered, "xapp.GetXappConfigListHandler")
 	}
@@ -284,6 +292,10 @@ func (o *XappFrameworkAPI) initHandlerCache() {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
 	o.handlers["GET"]["/subscriptions"] = common.NewGetAllSubscriptions(o.context, o.CommonGetAllSubscriptionsHandler)
+	if o.handlers["PUT"] == nil {
+		o.handlers["PUT"] = make(map[string]http.Handler)
+	}
+	o.handlers["PUT"]["/subscriptions/{subscriptionId}"] = common.NewModifySubscription(o.context, o.CommonModifySubscriptionHandler)
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -0,0 +1,609 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+// Package submgrfake is an in-process fake of the Subscription Manager REST API,
+// meant for testing xApp subscription flows without a cluster.
+package submgrfake
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/runtime/middleware"
+
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/models"
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/restapi"
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/restapi/operations"
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/restapi/operations/common"
+)
+
+const (
+	NotificationPath = "/ric/v1/subscriptions/response"
+	SignatureHeader  = "X-Ric-Signature" // Same as xapp.NotificationSignatureHeader
+)
+
+// Outcome scripts the handling of one request or one subscription instance.
+// The zero value is a successful request with an immediate notification.
+type Outcome struct {
+	StatusCode        int           // REST status code, 0 means success
+	Delay             time.Duration // Delay before the REST response, e.g. to trigger client timeouts
+	ErrorCause        string        // Instance fails with this cause
+	ErrorSource       string        // SUBMGR, RTMGR, DBAAS, ASN1 or E2Node
+	TimeoutType       string        // Instance fails with E2-Timeout, RTMGR-Timeout or DBAAS-Timeout
+	NotificationDelay time.Duration // Delay before the notification is sent
+	NoNotification    bool          // Notification is never sent
+	NotificationFirst bool          // Notifications are delivered before the REST response
+}
+
+func (o Outcome) failed() bool {
+	return o.ErrorCause != "" || o.ErrorSource != "" || o.TimeoutType != ""
+}
+
+type subscription struct {
+	id        string
+	numericId int64
+	params    *models.SubscriptionParams
+	instances map[int64]*models.SubscriptionInstance
+}
+
+// SubMgr serves the common subscription API (Subscribe, ModifySubscription,
+// Unsubscribe and GetAllSubscriptions) and notifies the xApp like the real Subscription Manager
+type SubMgr struct {
+	mux           sync.Mutex
+	listener      net.Listener
+	server        *http.Server
+	stop          chan struct{}
+	wg            sync.WaitGroup
+	subs          map[string]*subscription
+	nextId        int64
+	nextE2Id      int64
+	subscribe     []Outcome
+	unsubscribe   []Outcome
+	modify        []Outcome
+	instance      map[int64][]Outcome
+	notifications []*models.SubscriptionResponse
+	secret        []byte
+}
+
+func New() *SubMgr {
+	return &SubMgr{
+		subs:     make(map[string]*subscription),
+		instance: make(map[int64][]Outcome),
+	}
+}
+
+// Start serves the API on addr, "" or port 0 selects a free local port
+func (s *SubMgr) Start(addr string) error {
+	if addr == "" {
+		addr = "127.0.0.1:0"
+	}
+
+	swaggerSpec, err := loads.Embedded(restapi.SwaggerJSON, restapi.FlatSwaggerJSON)
+	if err != nil {
+		return err
+	}
+
+	api := operations.NewXappFrameworkAPI(swaggerSpec)
+	api.CommonSubscribeHandler = common.SubscribeHandlerFunc(s.handleSubscribe)
+	api.CommonUnsubscribeHandler = common.UnsubscribeHandlerFunc(s.handleUnsubscribe)
+	api.CommonGetAllSubscriptionsHandler = common.GetAllSubscriptionsHandlerFunc(s.handleQuery)
+	api.CommonModifySubscriptionHandler = common.ModifySubscriptionHandlerFunc(s.handleModify)
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	s.mux.Lock()
+	s.listener = l
+	s.server = &http.Server{Handler: api.Serve(nil)}
+	s.stop = make(chan struct{})
+	server := s.server
+	s.mux.Unlock()
+
+	go server.Serve(l)
+	return nil
+}
+
+// Stop shuts down the server and drops the pending notifications
+func (s *SubMgr) Stop() {
+	s.mux.Lock()
+	server, stop := s.server, s.stop
+	s.server, s.stop = nil, nil
+	s.mux.Unlock()
+
+	if server == nil {
+		return
+	}
+	close(stop)
+	server.Close()
+	s.wg.Wait()
+}
+
+// Host returns the "host:port" the fake is listening on, as expected by xapp.NewSubscriber
+func (s *SubMgr) Host() string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// ScriptSubscribe queues outcomes for the following subscribe requests, one per request
+func (s *SubMgr) ScriptSubscribe(o ...Outcome) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.subscribe = append(s.subscribe, o...)
+}
+
+// ScriptUnsubscribe queues outcomes for the following unsubscribe requests, one per request
+func (s *SubMgr) ScriptUnsubscribe(o ...Outcome) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.unsubscribe = append(s.unsubscribe, o...)
+}
+
+// ScriptModify queues outcomes for the following modify requests, one per request
+func (s *SubMgr) ScriptModify(o ...Outcome) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.modify = append(s.modify, o...)
+}
+
+// ScriptInstance queues outcomes for the following subscription details with the
+// given XappEventInstanceId. An instance outcome overrides the request outcome.
+func (s *SubMgr) ScriptInstance(xappEventInstanceID int64, o ...Outcome) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.instance[xappEventInstanceID] = append(s.instance[xappEventInstanceID], o...)
+}
+
+// Subscriptions returns the active subscriptions as GetAllSubscriptions would
+func (s *SubMgr) Subscriptions() models.SubscriptionList {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.list()
+}
+
+// Subscription returns the parameters of the latest request of a subscription
+func (s *SubMgr) Subscription(id string) (*models.SubscriptionParams, bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if sub, ok := s.subs[id]; ok {
+		return sub.params, true
+	}
+	return nil, false
+}
+
+// Notifications returns the notifications sent so far
+func (s *SubMgr) Notifications() []*models.SubscriptionResponse {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	n := make([]*models.SubscriptionResponse, len(s.notifications))
+	copy(n, s.notifications)
+	return n
+}
+
+// Reset drops all subscriptions, scripted outcomes and sent notifications
+func (s *SubMgr) Reset() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.subs = make(map[string]*subscription)
+	s.instance = make(map[int64][]Outcome)
+	s.subscribe = nil
+	s.unsubscribe = nil
+	s.modify = nil
+	s.notifications = nil
+}
+
+func (s *SubMgr) handleSubscribe(params common.SubscribeParams) middleware.Responder {
+	s.mux.Lock()
+	o := pop(&s.subscribe)
+	s.mux.Unlock()
+
+	if !s.sleep(o.Delay) {
+		return common.NewSubscribeServiceUnavailable()
+	}
+
+	switch o.StatusCode {
+	case 0, common.SubscribeCreatedCode:
+	case common.SubscribeBadRequestCode:
+		return common.NewSubscribeBadRequest()
+	case common.SubscribeNotFoundCode:
+		return common.NewSubscribeNotFound()
+	case common.SubscribeServiceUnavailableCode:
+		return common.NewSubscribeServiceUnavailable()
+	default:
+		return common.NewSubscribeInternalServerError()
+	}
+
+	p := params.SubscriptionParams
+	if p == nil || p.ClientEndpoint == nil || p.ClientEndpoint.HTTPPort == nil {
+		return common.NewSubscribeBadRequest()
+	}
+
+	s.mux.Lock()
+
+	// A known SubscriptionId is a retry of (some of) the subscription details
+	sub, ok := s.subs[p.SubscriptionID]
+	if !ok {
+		s.nextId++
+		sub = &subscription{
+			id:        p.SubscriptionID,
+			numericId: s.nextId,
+			instances: make(map[int64]*models.SubscriptionInstance),
+		}
+		if sub.id == "" {
+			sub.id = strconv.FormatInt(sub.numericId, 10)
+		} else if n, err := strconv.ParseInt(sub.id, 10, 64); err == nil {
+			sub.numericId = n
+		}
+		s.subs[sub.id] = sub
+	}
+	sub.params = p
+
+	sent := s.setup(sub, p, o)
+	id := sub.id
+	s.mux.Unlock()
+
+	if o.NotificationFirst {
+		wait(sent)
+	}
+	return common.NewSubscribeCreated().WithPayload(&models.SubscriptionResponse{
+		SubscriptionID:        &id,
+		SubscriptionInstances: []*models.SubscriptionInstance{},
+	})
+}
+
+func (s *SubMgr) handleModify(params common.ModifySubscriptionParams) middleware.Responder {
+	s.mux.Lock()
+	o := pop(&s.modify)
+	s.mux.Unlock()
+
+	if !s.sleep(o.Delay) {
+		return common.NewModifySubscriptionServiceUnavailable()
+	}
+
+	switch o.StatusCode {
+	case 0, common.ModifySubscriptionOKCode:
+	case common.ModifySubscriptionBadRequestCode:
+		return common.NewModifySubscriptionBadRequest()
+	case common.ModifySubscriptionNotFoundCode:
+		return common.NewModifySubscriptionNotFound()
+	case common.ModifySubscriptionServiceUnavailableCode:
+		return common.NewModifySubscriptionServiceUnavailable()
+	default:
+		return common.NewModifySubscriptionInternalServerError()
+	}
+
+	p := params.SubscriptionParams
+	if p == nil || p.ClientEndpoint == nil || p.ClientEndpoint.HTTPPort == nil {
+		return common.NewModifySubscriptionBadRequest()
+	}
+
+	s.mux.Lock()
+
+	sub, ok := s.subs[params.SubscriptionID]
+	if !ok {
+		s.mux.Unlock()
+		return common.NewModifySubscriptionNotFound()
+	}
+	sub.params = p
+
+	// Instances not in the new details are deleted
+	wanted := make(map[int64]bool)
+	for _, d := range p.SubscriptionDetails {
+		if d != nil && d.XappEventInstanceID != nil {
+			wanted[*d.XappEventInstanceID] = true
+		}
+	}
+	for xappId := range sub.instances {
+		if !wanted[xappId] {
+			delete(sub.instances, xappId)
+		}
+	}
+	sent := s.setup(sub, p, o)
+	id := sub.id
+	s.mux.Unlock()
+
+	if o.NotificationFirst {
+		wait(sent)
+	}
+	return common.NewModifySubscriptionOK().WithPayload(&models.SubscriptionResponse{
+		SubscriptionID:        &id,
+		SubscriptionInstances: []*models.SubscriptionInstance{},
+	})
+}
+
+func (s *SubMgr) handleUnsubscribe(params common.UnsubscribeParams) middleware.Responder {
+	s.mux.Lock()
+	o := pop(&s.unsubscribe)
+	s.mux.Unlock()
+
+	if !s.sleep(o.Delay) {
+		return common.NewUnsubscribeInternalServerError()
+	}
+
+	switch o.StatusCode {
+	case 0, common.UnsubscribeNoContentCode:
+	case common.UnsubscribeBadRequestCode:
+		return common.NewUnsubscribeBadRequest()
+	default:
+		return common.NewUnsubscribeInternalServerError()
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if _, ok := s.subs[params.SubscriptionID]; !ok {
+		return common.NewUnsubscribeBadRequest()
+	}
+	delete(s.subs, params.SubscriptionID)
+	return common.NewUnsubscribeNoContent()
+}
+
+func (s *SubMgr) handleQuery(params common.GetAllSubscriptionsParams) middleware.Responder {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	matching := models.SubscriptionList{}
+	for _, data := range s.list() {
+		if match(params, data) {
+			matching = append(matching, data)
+		}
+	}
+
+	total := int64(len(matching))
+	start, end := int64(0), total
+	if params.Offset != nil && *params.Offset > 0 {
+		start = *params.Offset
+		if start > total {
+			start = total
+		}
+	}
+	if params.Limit != nil && *params.Limit >= 0 && *params.Limit < total-start {
+		end = start + *params.Limit
+	}
+	return common.NewGetAllSubscriptionsOK().WithPayload(matching[start:end]).WithXTotalCount(total)
+}
+
+func match(params common.GetAllSubscriptionsParams, data *models.SubscriptionData) bool {
+	if params.Meid != nil && *params.Meid != data.Meid {
+		return false
+	}
+	if params.RanFunctionID != nil && (data.RANFunctionID == nil || *params.RanFunctionID != *data.RANFunctionID) {
+		return false
+	}
+	if params.SubscriptionIDPrefix != nil && !strings.HasPrefix(strconv.FormatInt(data.SubscriptionID, 10), *params.SubscriptionIDPrefix) {
+		return false
+	}
+	if params.ClientEndpoint != nil {
+		for _, ep := range data.ClientEndpoint {
+			if ep == *params.ClientEndpoint {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// setup sets up the subscription details and notifies the outcome of each, s.mux must be held.
+// Returns the notifications sent, closed once delivered.
+func (s *SubMgr) setup(sub *subscription, p *models.SubscriptionParams, o Outcome) (sent []chan struct{}) {
+	for _, d := range p.SubscriptionDetails {
+		if d == nil || d.XappEventInstanceID == nil {
+			continue
+		}
+
+		io := o
+		if scripted, ok := s.instance[*d.XappEventInstanceID]; ok && len(scripted) > 0 {
+			io = pop(&scripted)
+			s.instance[*d.XappEventInstanceID] = scripted
+		}
+
+		inst := &models.SubscriptionInstance{XappEventInstanceID: d.XappEventInstanceID}
+		e2Id := int64(0)
+		if io.failed() {
+			inst.ErrorCause = io.ErrorCause
+			inst.ErrorSource = io.ErrorSource
+			inst.TimeoutType = io.TimeoutType
+			delete(sub.instances, *d.XappEventInstanceID)
+		} else {
+			// A modified instance keeps its E2EventInstanceId
+			if old, ok := sub.instances[*d.XappEventInstanceID]; ok {
+				e2Id = *old.E2EventInstanceID
+			} else {
+				e2Id = s.allocE2Id()
+			}
+			sub.instances[*d.XappEventInstanceID] = inst
+		}
+		inst.E2EventInstanceID = &e2Id
+
+		if !io.NoNotification {
+			sent = append(sent, s.notify(sub.id, inst, *p.ClientEndpoint, io.NotificationDelay))
+		}
+	}
+	return
+}
+
+// wait waits for the notifications to be delivered
+func wait(sent []chan struct{}) {
+	for _, done := range sent {
+		<-done
+	}
+}
+
+// list returns the subscriptions ordered by ID, s.mux must be held
+func (s *SubMgr) list() models.SubscriptionList {
+	resp := models.SubscriptionList{}
+	for _, sub := range s.subs {
+		data := &models.SubscriptionData{
+			SubscriptionID:        sub.numericId,
+			ClientEndpoint:        []string{},
+			SubscriptionInstances: []*models.SubscriptionInstance{},
+		}
+		if sub.params.Meid != nil {
+			data.Meid = *sub.params.Meid
+		}
+		data.RANFunctionID = sub.params.RANFunctionID
+		if ep := sub.params.ClientEndpoint; ep != nil {
+			if ep.HTTPPort != nil {
+				data.ClientEndpoint = append(data.ClientEndpoint, fmt.Sprintf("%s:%d", ep.Host, *ep.HTTPPort))
+			}
+			if ep.RMRPort != nil {
+				data.ClientEndpoint = append(data.ClientEndpoint, fmt.Sprintf("%s:%d", ep.Host, *ep.RMRPort))
+			}
+		}
+		for _, inst := range sub.instances {
+			data.SubscriptionInstances = append(data.SubscriptionInstances, inst)
+		}
+		sort.Slice(data.SubscriptionInstances, func(i, j int) bool {
+			return *data.SubscriptionInstances[i].XappEventInstanceID < *data.SubscriptionInstances[j].XappEventInstanceID
+		})
+		resp = append(resp, data)
+	}
+
+	sort.Slice(resp, func(i, j int) bool { return resp[i].SubscriptionID < resp[j].SubscriptionID })
+	return resp
+}
+
+// allocE2Id returns the next free E2EventInstanceId (1..65535), s.mux must be held
+func (s *SubMgr) allocE2Id() int64 {
+	for {
+		s.nextE2Id = s.nextE2Id%65535 + 1
+		if !s.e2IdInUse(s.nextE2Id) {
+			return s.nextE2Id
+		}
+	}
+}
+
+func (s *SubMgr) e2IdInUse(id int64) bool {
+	for _, sub := range s.subs {
+		for _, inst := range sub.instances {
+			if *inst.E2EventInstanceID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetNotificationSecret signs the notifications with HMAC-SHA256 using secret, nil disables
+func (s *SubMgr) SetNotificationSecret(secret []byte) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.secret = secret
+}
+
+// notify sends the instance to the client in the background, s.mux must be held.
+// The returned channel is closed once the notification is sent.
+func (s *SubMgr) notify(id string, inst *models.SubscriptionInstance, ep models.SubscriptionParamsClientEndpoint, delay time.Duration) chan struct{} {
+	resp := &models.SubscriptionResponse{
+		SubscriptionID:        &id,
+		SubscriptionInstances: []*models.SubscriptionInstance{inst},
+	}
+	clientUrl := fmt.Sprintf("http://%s:%d%s", ep.Host, *ep.HTTPPort, NotificationPath)
+
+	done := make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(done)
+
+		if !s.sleep(delay) {
+			return
+		}
+
+		s.mux.Lock()
+		s.notifications = append(s.notifications, resp)
+		secret := s.secret
+		s.mux.Unlock()
+
+		data, err := json.Marshal(resp)
+		if err != nil {
+			log.Printf("submgrfake: json.Marshal failed: %v", err)
+			return
+		}
+		req, err := http.NewRequest(http.MethodPost, clientUrl, bytes.NewBuffer(data))
+		if err != nil {
+			log.Printf("submgrfake: http.NewRequest failed: %v", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if len(secret) != 0 {
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(data)
+			req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		}
+		r, err := http.DefaultClient.Do(req)
+		if err != nil {
+			log.Printf("submgrfake: notification to %s failed: %v", clientUrl, err)
+			return
+		}
+		r.Body.Close()
+		if r.StatusCode != http.StatusOK {
+			log.Printf("submgrfake: notification to %s failed: statusCode=%d", clientUrl, r.StatusCode)
+		}
+	}()
+	return done
+}
+
+// sleep waits for d, returns false if the fake was stopped meanwhile
+func (s *SubMgr) sleep(d time.Duration) bool {
+	s.mux.Lock()
+	stop := s.stop
+	s.mux.Unlock()
+
+	if d <= 0 {
+		return true
+	}
+	select {
+	case <-time.After(d):
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+func pop(q *[]Outcome) (o Outcome) {
+	if len(*q) > 0 {
+		o = (*q)[0]
+		*q = (*q)[1:]
+	}
+	return
+}
@@ -0,0 +1,246 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package submgrfake
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/stretchr/testify/assert"
+
+	apiclient "gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientapi"
+	apicommon "gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientapi/common"
+	apimodel "gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientmodel"
+)
+
+func startFake(t *testing.T) (*SubMgr, *apiclient.RICSubscription) {
+	fake := New()
+	assert.Nil(t, fake.Start(""))
+	return fake, apiclient.New(httptransport.New(fake.Host(), "/ric/v1", []string{"http"}), strfmt.Default)
+}
+
+// startClient serves the xApp side notification endpoint
+func startClient(t *testing.T) (*httptest.Server, chan *apimodel.SubscriptionResponse, string, int64) {
+	ch := make(chan *apimodel.SubscriptionResponse, 10)
+	mux := http.NewServeMux()
+	mux.HandleFunc(NotificationPath, func(w http.ResponseWriter, r *http.Request) {
+		resp := &apimodel.SubscriptionResponse{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(resp))
+		ch <- resp
+	})
+	server := httptest.NewServer(mux)
+
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	p, _ := strconv.ParseInt(port, 10, 64)
+	return server, ch, host, p
+}
+
+func params(host string, port int64, instanceIds ...int64) *apimodel.SubscriptionParams {
+	meid := "gnb123456"
+	funId := int64(1)
+	rmrPort := int64(4560)
+	p := &apimodel.SubscriptionParams{
+		Meid:           &meid,
+		RANFunctionID:  &funId,
+		ClientEndpoint: &apimodel.SubscriptionParamsClientEndpoint{Host: host, HTTPPort: &port, RMRPort: &rmrPort},
+	}
+	for _, id := range instanceIds {
+		id := id
+		actionId := int64(1)
+		actionType := apimodel.ActionToBeSetupActionTypeReport
+		p.SubscriptionDetails = append(p.SubscriptionDetails, &apimodel.SubscriptionDetail{
+			XappEventInstanceID: &id,
+			EventTriggers:       apimodel.EventTriggerDefinition{1, 2},
+			ActionToBeSetupList: apimodel.ActionsToBeSetup{{ActionID: &actionId, ActionType: &actionType}},
+		})
+	}
+	return p
+}
+
+func waitNotification(t *testing.T, ch chan *apimodel.SubscriptionResponse) *apimodel.SubscriptionResponse {
+	select {
+	case resp := <-ch:
+		return resp
+	case <-time.After(2 * time.Second):
+		t.Fatal("notification not received")
+	}
+	return nil
+}
+
+func TestFakeSubscribeNotifiesAndLists(t *testing.T) {
+	fake, client := startFake(t)
+	defer fake.Stop()
+	server, ch, host, port := startClient(t)
+	defer server.Close()
+
+	result, err := client.Common.Subscribe(apicommon.NewSubscribeParams().WithSubscriptionParams(params(host, port, 11, 12)))
+	assert.Nil(t, err)
+	id := *result.Payload.SubscriptionID
+	assert.Equal(t, "1", id)
+
+	e2Ids := make(map[int64]bool)
+	for i := 0; i < 2; i++ {
+		resp := waitNotification(t, ch)
+		assert.Equal(t, id, *resp.SubscriptionID)
+		assert.Equal(t, 1, len(resp.SubscriptionInstances))
+		assert.Equal(t, "", resp.SubscriptionInstances[0].ErrorCause)
+		e2Ids[*resp.SubscriptionInstances[0].E2EventInstanceID] = true
+	}
+	assert.Equal(t, map[int64]bool{1: true, 2: true}, e2Ids)
+
+	list, err := client.Common.GetAllSubscriptions(apicommon.NewGetAllSubscriptionsParams())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(list.Payload))
+	assert.Equal(t, int64(1), list.Payload[0].SubscriptionID)
+	assert.Equal(t, "gnb123456", list.Payload[0].Meid)
+	assert.Equal(t, []string{host + ":" + strconv.FormatInt(port, 10), host + ":4560"}, list.Payload[0].ClientEndpoint)
+	assert.Equal(t, 2, len(list.Payload[0].SubscriptionInstances))
+
+	_, err = client.Common.Unsubscribe(apicommon.NewUnsubscribeParams().WithSubscriptionID(id))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(fake.Subscriptions()))
+
+	_, err = client.Common.Unsubscribe(apicommon.NewUnsubscribeParams().WithSubscriptionID(id))
+	assert.NotNil(t, err)
+}
+
+func TestFakeScriptedFailures(t *testing.T) {
+	fake, client := startFake(t)
+	defer fake.Stop()
+	server, ch, host, port := startClient(t)
+	defer server.Close()
+
+	fake.ScriptSubscribe(Outcome{StatusCode: http.StatusServiceUnavailable})
+	_, err := client.Common.Subscribe(apicommon.NewSubscribeParams().WithSubscriptionParams(params(host, port, 11)))
+	assert.NotNil(t, err)
+
+	fake.ScriptInstance(11, Outcome{ErrorSource: "E2Node", TimeoutType: "E2-Timeout", ErrorCause: "timeout"})
+	result, err := client.Common.Subscribe(apicommon.NewSubscribeParams().WithSubscriptionParams(params(host, port, 11)))
+	assert.Nil(t, err)
+	resp := waitNotification(t, ch)
+	assert.Equal(t, "E2-Timeout", resp.SubscriptionInstances[0].TimeoutType)
+	assert.Equal(t, int64(0), *resp.SubscriptionInstances[0].E2EventInstanceID)
+
+	// Retry with the same subscription ID succeeds
+	p := params(host, port, 11)
+	p.SubscriptionID = *result.Payload.SubscriptionID
+	_, err = client.Common.Subscribe(apicommon.NewSubscribeParams().WithSubscriptionParams(p))
+	assert.Nil(t, err)
+	resp = waitNotification(t, ch)
+	assert.Equal(t, *result.Payload.SubscriptionID, *resp.SubscriptionID)
+	assert.Equal(t, "", resp.SubscriptionInstances[0].TimeoutType)
+	assert.Equal(t, 1, len(fake.Subscriptions()))
+	assert.Equal(t, 2, len(fake.Notifications()))
+}
+
+func TestFakeScriptedTimeout(t *testing.T) {
+	fake, client := startFake(t)
+	defer fake.Stop()
+	server, ch, host, port := startClient(t)
+	defer server.Close()
+
+	fake.ScriptSubscribe(Outcome{Delay: 2 * time.Second})
+	_, err := client.Common.Subscribe(apicommon.NewSubscribeParamsWithTimeout(100 * time.Millisecond).WithSubscriptionParams(params(host, port, 11)))
+	assert.NotNil(t, err)
+
+	fake.ScriptSubscribe(Outcome{NoNotification: true})
+	_, err = client.Common.Subscribe(apicommon.NewSubscribeParams().WithSubscriptionParams(params(host, port, 11)))
+	assert.Nil(t, err)
+	select {
+	case <-ch:
+		t.Error("unexpected notification")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestFakeModify(t *testing.T) {
+	fake, client := startFake(t)
+	defer fake.Stop()
+	server, ch, host, port := startClient(t)
+	defer server.Close()
+
+	result, err := client.Common.Subscribe(apicommon.NewSubscribeParams().WithSubscriptionParams(params(host, port, 11, 12)))
+	assert.Nil(t, err)
+	id := *result.Payload.SubscriptionID
+	e2Ids := make(map[int64]int64)
+	for i := 0; i < 2; i++ {
+		resp := waitNotification(t, ch)
+		e2Ids[*resp.SubscriptionInstances[0].XappEventInstanceID] = *resp.SubscriptionInstances[0].E2EventInstanceID
+	}
+
+	// Instance 12 is dropped and 13 added, 11 keeps its E2EventInstanceId
+	_, err = client.Common.ModifySubscription(apicommon.NewModifySubscriptionParams().WithSubscriptionID(id).WithSubscriptionParams(params(host, port, 11, 13)))
+	assert.Nil(t, err)
+	for i := 0; i < 2; i++ {
+		resp := waitNotification(t, ch)
+		if *resp.SubscriptionInstances[0].XappEventInstanceID == 11 {
+			assert.Equal(t, e2Ids[11], *resp.SubscriptionInstances[0].E2EventInstanceID)
+		}
+	}
+
+	list := fake.Subscriptions()
+	assert.Equal(t, 1, len(list))
+	assert.Equal(t, 2, len(list[0].SubscriptionInstances))
+	assert.Equal(t, int64(11), *list[0].SubscriptionInstances[0].XappEventInstanceID)
+	assert.Equal(t, int64(13), *list[0].SubscriptionInstances[1].XappEventInstanceID)
+
+	_, err = client.Common.ModifySubscription(apicommon.NewModifySubscriptionParams().WithSubscriptionID("unknown").WithSubscriptionParams(params(host, port, 11)))
+	assert.IsType(t, &apicommon.ModifySubscriptionNotFound{}, err)
+}
+
+func TestFakeQueryFilters(t *testing.T) {
+	fake, client := startFake(t)
+	defer fake.Stop()
+	server, _, host, port := startClient(t)
+	defer server.Close()
+
+	for i := 0; i < 3; i++ {
+		fake.ScriptSubscribe(Outcome{NoNotification: true})
+		_, err := client.Common.Subscribe(apicommon.NewSubscribeParams().WithSubscriptionParams(params(host, port, 11)))
+		assert.Nil(t, err)
+	}
+
+	meid, offset, limit := "gnb123456", int64(1), int64(1)
+	list, err := client.Common.GetAllSubscriptions(apicommon.NewGetAllSubscriptionsParams().WithMeid(&meid).WithOffset(&offset).WithLimit(&limit))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), list.XTotalCount)
+	assert.Equal(t, 1, len(list.Payload))
+	assert.Equal(t, int64(2), list.Payload[0].SubscriptionID)
+
+	limit = math.MaxInt64
+	list, err = client.Common.GetAllSubscriptions(apicommon.NewGetAllSubscriptionsParams().WithMeid(&meid).WithOffset(&offset).WithLimit(&limit))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), list.XTotalCount)
+	assert.Equal(t, 2, len(list.Payload))
+
+	meid = "other"
+	list, err = client.Common.GetAllSubscriptions(apicommon.NewGetAllSubscriptionsParams().WithMeid(&meid))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), list.XTotalCount)
+	assert.Equal(t, 0, len(list.Payload))
+}
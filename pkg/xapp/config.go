@@ -26,6 +26,7 @@ import (
 	"github.com/spf13/viper"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 //-----------------------------------------------------------------------------
@@ -46,22 +47,34 @@ type SDLNotificationCB func(string, ...string)
 
 var ConfigChangeListeners []ConfigChangeCB
 
-func parseCmd() string {
-	var fileName *string
+var configChanged = func(source string) {}
+
+func parseCmd() (string, []string, []string) {
+	var fileName, overlays *string
+	var sets configSetFlags
 	fileName = flag.String("f", os.Getenv("CFG_FILE"), "Specify the configuration file.")
+	overlays = flag.String("config-overlays", os.Getenv("CFG_OVERLAYS"), "Specify comma separated configuration overlay files.")
+	flag.Var(&sets, "config-set", "Set a configuration key, e.g. -config-set controls.logger.level=4. May be repeated.")
 	flag.Parse()
 
-	return *fileName
+	var files []string
+	if *overlays != "" {
+		files = strings.Split(*overlays, ",")
+	}
+	return *fileName, files, sets
 }
 
 func LoadConfig() (l *Log) {
 	l = NewLogger(filepath.Base(os.Args[0]))
-	viper.SetConfigFile(parseCmd())
+	cfgFile, overlays, sets := parseCmd()
+	viper.SetConfigFile(cfgFile)
 
 	if err := viper.ReadInConfig(); err != nil {
 		l.Error("Reading config file failed: %v", err.Error())
 	}
 	l.Info("Using config file: %s", viper.ConfigFileUsed())
+	configStatus.load(l, viper.ConfigFileUsed(), overlays, sets)
+	configEventStatus.update(viper.ConfigFileUsed())
 
 	updateMTypes := func() {
 		var mtypes []mtype
@@ -94,22 +107,37 @@ func LoadConfig() (l *Log) {
 
 	updateMTypes()
 
-	viper.WatchConfig()
-	viper.OnConfigChange(func(e fsnotify.Event) {
-		l.Info("config file %s changed ", e.Name)
-
+	// Applies a validated config change, from the file or from CM_UPDATE
+	configChanged = func(source string) {
 		updateMTypes()
 		if viper.IsSet("controls.logger.level") {
 			Logger.SetLevel(viper.GetInt("controls.logger.level"))
 		} else {
 			Logger.SetLevel(viper.GetInt("logger.level"))
 		}
+		Logger.SetBufferSize(configLogBufferSize())
+		Logger.SetComponentLevels(configComponentLevels())
+		if err := Logger.SetOutputFormat(viper.GetString("controls.logger.format")); err != nil {
+			Logger.Error("Invalid controls.logger.format: %v", err)
+		}
+
+		configEventStatus.update(source)
+		configVersions.record(source)
 
 		if len(ConfigChangeListeners) > 0 {
 			for _, f := range ConfigChangeListeners {
-				go f(e.Name)
+				go f(source)
 			}
 		}
+	}
+
+	viper.WatchConfig()
+	viper.OnConfigChange(func(e fsnotify.Event) {
+		l.Info("config file %s changed ", e.Name)
+		if !configStatus.reload(e.Name) {
+			return
+		}
+		configChanged(e.Name)
 	})
 
 	return
@@ -0,0 +1,168 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
+)
+
+// ConfigKeyURL reads and modifies one key of the running config, e.g. controls.subscription.timeout
+const ConfigKeyURL = "/ric/v1/config/{keyPath}"
+
+// ConfigKeyValue is the body of GET and PUT ConfigKeyURL
+type ConfigKeyValue struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Source string      `json:"source,omitempty"`
+}
+
+// ConfigKeyUpdate is the response of PUT ConfigKeyURL
+type ConfigKeyUpdate struct {
+	Key     string      `json:"key"`
+	Old     interface{} `json:"old"`
+	New     interface{} `json:"new"`
+	DryRun  bool        `json:"dryRun"`
+	Applied bool        `json:"applied"`
+}
+
+// SetKey sets one existing key of the running config. The new value must have the
+// same JSON type as the current one, and the resulting config must pass the schema
+// validation. With dryRun the change is only validated. The key is case-insensitive.
+func (*Configurator) SetKey(key string, value interface{}, dryRun bool) (*ConfigKeyUpdate, error) {
+	return setConfigKey(key, value, dryRun, "")
+}
+
+// setConfigKey is SetKey with the source recorded into the config history and events,
+// "rest:<key>" if empty
+func setConfigKey(key string, value interface{}, dryRun bool, source string) (*ConfigKeyUpdate, error) {
+	key = strings.ToLower(key)
+	if !viper.IsSet(key) {
+		return nil, fmt.Errorf("config key '%s' not found", key)
+	}
+	old := viper.Get(key)
+	if a, b := configValueType(old), configValueType(value); a != b {
+		return nil, fmt.Errorf("config key '%s' is %s, not %s", key, a, b)
+	}
+
+	update := configKeyMap(key, value)
+	res := &ConfigKeyUpdate{Key: key, Old: configStatus.redactSecrets(key, old), New: value, DryRun: dryRun}
+	if dryRun {
+		return res, configStatus.check(update)
+	}
+
+	if source == "" {
+		source = "rest:" + key
+	}
+	if err := configStatus.apply(source, update); err != nil {
+		return nil, err
+	}
+	configChanged(source)
+	res.Applied = true
+	return res, nil
+}
+
+// configKeyMap returns the nested map setting the dotted key path to value
+func configKeyMap(key string, value interface{}) map[string]interface{} {
+	path := strings.Split(key, ".")
+	m := map[string]interface{}{path[len(path)-1]: value}
+	for i := len(path) - 2; i >= 0; i-- {
+		m = map[string]interface{}{path[i]: m}
+	}
+	return m
+}
+
+// configValueType returns the JSON type of the value, numbers of any Go type are "number"
+func configValueType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		return "number"
+	case []interface{}, []string, []int:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func configKeyGetHandler(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["keyPath"]
+	if !viper.IsSet(key) {
+		respondWithError(w, http.StatusNotFound, fmt.Sprintf("config key '%s' not found", key))
+		return
+	}
+	// The content of a secret file is not shown, only its reference
+	value := configStatus.redactSecrets(key, viper.Get(key))
+	respondWithJSON(w, http.StatusOK, ConfigKeyValue{Key: key, Value: value, Source: Config.Source(key)})
+}
+
+func configKeyPutHandler(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["keyPath"]
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dryRun"))
+
+	if r.Body == nil {
+		respondWithError(w, http.StatusBadRequest, "no body")
+		return
+	}
+	defer r.Body.Close()
+
+	var body ConfigKeyValue
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+		return
+	}
+	if !viper.IsSet(key) {
+		respondWithError(w, http.StatusNotFound, fmt.Sprintf("config key '%s' not found", key))
+		return
+	}
+
+	// The requester is recorded as the source of the new config version. The values of
+	// secret keys are not written to the audit log.
+	old, value := configStatus.redactSecrets(key, viper.Get(key)), body.Value
+	if configStatus.isSecret(key) {
+		value = configRedactedValue
+	}
+	res, err := setConfigKey(key, body.Value, dryRun, fmt.Sprintf("rest:%s remote=%s", strings.ToLower(key), r.RemoteAddr))
+	Logger.Info("Config audit: PUT key=%s old=%v new=%v dryRun=%t remote=%s result=%v", key, old, value, dryRun, r.RemoteAddr, errorOrOk(err))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, res)
+}
+
+func errorOrOk(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return "ok"
+}
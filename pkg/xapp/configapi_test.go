@@ -0,0 +1,103 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func putConfigKey(t *testing.T, url, body string) (int, *ConfigKeyUpdate) {
+	req, _ := http.NewRequest("PUT", url, bytes.NewBufferString(body))
+	response := executeRequest(req, nil)
+	res := &ConfigKeyUpdate{}
+	if response.Code == http.StatusOK {
+		assert.Nil(t, json.NewDecoder(response.Body).Decode(res))
+	}
+	return response.Code, res
+}
+
+func TestConfigKeyGet(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/ric/v1/config/controls.logger.level", nil)
+	response := executeRequest(req, nil)
+	checkResponseCode(t, http.StatusOK, response.Code)
+
+	var v ConfigKeyValue
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&v))
+	assert.Equal(t, "controls.logger.level", v.Key)
+	assert.Equal(t, float64(3), v.Value)
+
+	req, _ = http.NewRequest("GET", "/ric/v1/config/controls.nosuchkey", nil)
+	response = executeRequest(req, nil)
+	checkResponseCode(t, http.StatusNotFound, response.Code)
+}
+
+func TestConfigKeyPut(t *testing.T) {
+	isolateConfig(t)
+	url := "/ric/v1/config/controls.subscription.retryDelay"
+	assert.Equal(t, 5, viper.GetInt("controls.subscription.retryDelay"))
+
+	code, res := putConfigKey(t, url+"?dryRun=true", `{"value": 7}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, res.DryRun)
+	assert.False(t, res.Applied)
+	assert.Equal(t, 5, viper.GetInt("controls.subscription.retryDelay"))
+
+	code, _ = putConfigKey(t, url, `{"value": "seven"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = putConfigKey(t, url, `{"value": `)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = putConfigKey(t, "/ric/v1/config/controls.nosuchkey", `{"value": 1}`)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	assert.Nil(t, Config.SetSchema([]byte(`{"properties": {"subscription": {"properties": {"retryDelay": {"maximum": 10}}}}}`)))
+	defer Config.SetSchema(nil)
+	code, _ = putConfigKey(t, url+"?dryRun=true", `{"value": 11}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, res = putConfigKey(t, url, `{"value": 7}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, res.Applied)
+	assert.Equal(t, float64(5), res.Old)
+	assert.Equal(t, 7, viper.GetInt("controls.subscription.retryDelay"))
+
+	// The key is case-insensitive, the change is recorded with the requester in the history
+	code, _ = putConfigKey(t, "/ric/v1/config/controls.SUBSCRIPTION.retrydelay", `{"value": 8}`)
+	assert.Equal(t, http.StatusOK, code)
+	v, err := Config.ConfigVersion(configVersions.current())
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(v.Source, "rest:controls.subscription.retrydelay remote="), v.Source)
+
+	body, _ := ioutil.ReadFile(viper.ConfigFileUsed())
+	var doc map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(liveConfigDocument(body)), &doc))
+	controls := doc["controls"].(map[string]interface{})
+	assert.NotContains(t, controls, "SUBSCRIPTION")
+	subscription := controls["subscription"].(map[string]interface{})
+	assert.Equal(t, float64(8), subscription["retryDelay"])
+	assert.NotContains(t, subscription, "retrydelay")
+}
@@ -0,0 +1,201 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+type ConfigChangeType string
+
+const (
+	ConfigKeyAdded    ConfigChangeType = "added"
+	ConfigKeyRemoved  ConfigChangeType = "removed"
+	ConfigKeyModified ConfigChangeType = "modified"
+)
+
+// ConfigChange is the change of one leaf key path, e.g. "controls.logger.level".
+// Old is nil for added keys and New for removed keys.
+type ConfigChange struct {
+	Key  string
+	Type ConfigChangeType
+	Old  interface{}
+	New  interface{}
+}
+
+// ConfigChangeEvent carries the changes of one config update ordered by key
+type ConfigChangeEvent struct {
+	Source  string // File name or other origin of the update
+	Changes []ConfigChange
+}
+
+// Keys returns the changed key paths
+func (e ConfigChangeEvent) Keys() []string {
+	keys := make([]string, 0, len(e.Changes))
+	for _, c := range e.Changes {
+		keys = append(keys, c.Key)
+	}
+	return keys
+}
+
+// Get returns the change of key, if any
+func (e ConfigChangeEvent) Get(key string) (ConfigChange, bool) {
+	key = strings.ToLower(key)
+	for _, c := range e.Changes {
+		if c.Key == key {
+			return c, true
+		}
+	}
+	return ConfigChange{}, false
+}
+
+type ConfigEventCB func(ConfigChangeEvent)
+
+type configEventListener struct {
+	prefix string
+	cb     ConfigEventCB
+}
+
+// -----------------------------------------------------------------------------
+// configEvents computes the difference of the consecutive configs and delivers
+// it to the listeners. Events are delivered one at a time in the order of the
+// updates, and listeners in the order of registration.
+// -----------------------------------------------------------------------------
+type configEvents struct {
+	mux       sync.Mutex
+	snapshot  map[string]interface{}
+	listeners []configEventListener
+	queue     []ConfigChangeEvent
+	running   bool
+}
+
+var configEventStatus = &configEvents{}
+
+// AddConfigEventListener registers f for the changes of keys under prefix, e.g.
+// "controls.logger" ("" for all keys). The event passed to f contains only those changes.
+func AddConfigEventListener(prefix string, f ConfigEventCB) {
+	configEventStatus.mux.Lock()
+	defer configEventStatus.mux.Unlock()
+	configEventStatus.listeners = append(configEventStatus.listeners, configEventListener{strings.ToLower(prefix), f})
+}
+
+// update takes a snapshot of the current config and queues the difference to the previous one
+func (c *configEvents) update(source string) {
+	c.publish(source, flattenConfig(viper.AllSettings()))
+}
+
+func (c *configEvents) publish(source string, snapshot map[string]interface{}) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	prev := c.snapshot
+	c.snapshot = snapshot
+	if prev == nil {
+		return
+	}
+
+	changes := diffConfig(prev, snapshot)
+	if len(changes) == 0 {
+		return
+	}
+	c.queue = append(c.queue, ConfigChangeEvent{Source: source, Changes: changes})
+	if !c.running {
+		c.running = true
+		go c.deliver()
+	}
+}
+
+func (c *configEvents) deliver() {
+	for {
+		c.mux.Lock()
+		if len(c.queue) == 0 {
+			c.running = false
+			c.mux.Unlock()
+			return
+		}
+		ev := c.queue[0]
+		c.queue = c.queue[1:]
+		listeners := append([]configEventListener{}, c.listeners...)
+		c.mux.Unlock()
+
+		for _, l := range listeners {
+			if filtered := ev.filter(l.prefix); len(filtered.Changes) > 0 {
+				l.cb(filtered)
+			}
+		}
+	}
+}
+
+func (e ConfigChangeEvent) filter(prefix string) ConfigChangeEvent {
+	if prefix == "" {
+		return e
+	}
+	f := ConfigChangeEvent{Source: e.Source}
+	for _, c := range e.Changes {
+		if c.Key == prefix || strings.HasPrefix(c.Key, prefix+".") {
+			f.Changes = append(f.Changes, c)
+		}
+	}
+	return f
+}
+
+// flattenConfig maps the leaf key paths to their values, lists are leaves
+func flattenConfig(settings map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{})
+	var walk func(prefix string, m map[string]interface{})
+	walk = func(prefix string, m map[string]interface{}) {
+		for k, v := range m {
+			key := strings.ToLower(k)
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			if sub, ok := v.(map[string]interface{}); ok && len(sub) > 0 {
+				walk(key, sub)
+			} else {
+				flat[key] = v
+			}
+		}
+	}
+	walk("", settings)
+	return flat
+}
+
+func diffConfig(prev, next map[string]interface{}) []ConfigChange {
+	var changes []ConfigChange
+	for k, old := range prev {
+		if v, ok := next[k]; !ok {
+			changes = append(changes, ConfigChange{Key: k, Type: ConfigKeyRemoved, Old: old})
+		} else if !reflect.DeepEqual(old, v) {
+			changes = append(changes, ConfigChange{Key: k, Type: ConfigKeyModified, Old: old, New: v})
+		}
+	}
+	for k, v := range next {
+		if _, ok := prev[k]; !ok {
+			changes = append(changes, ConfigChange{Key: k, Type: ConfigKeyAdded, New: v})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
@@ -0,0 +1,91 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigDiff(t *testing.T) {
+	prev := flattenConfig(map[string]interface{}{
+		"controls": map[string]interface{}{
+			"logger":    map[string]interface{}{"level": 3},
+			"threshold": 10,
+			"hosts":     []interface{}{"a", "b"},
+		},
+		"name": "xapp",
+	})
+	next := flattenConfig(map[string]interface{}{
+		"controls": map[string]interface{}{
+			"logger": map[string]interface{}{"level": 4},
+			"hosts":  []interface{}{"a", "b"},
+			"limit":  5,
+		},
+		"name": "xapp",
+	})
+
+	changes := diffConfig(prev, next)
+	assert.Equal(t, []ConfigChange{
+		{Key: "controls.limit", Type: ConfigKeyAdded, New: 5},
+		{Key: "controls.logger.level", Type: ConfigKeyModified, Old: 3, New: 4},
+		{Key: "controls.threshold", Type: ConfigKeyRemoved, Old: 10},
+	}, changes)
+	assert.Equal(t, 0, len(diffConfig(next, next)))
+}
+
+func TestConfigEventsFilteredAndOrdered(t *testing.T) {
+	c := &configEvents{}
+	levels := make(chan ConfigChangeEvent, 10)
+	all := make(chan ConfigChangeEvent, 10)
+	c.listeners = []configEventListener{
+		{"controls.logger.level", func(e ConfigChangeEvent) { levels <- e }},
+		{"", func(e ConfigChangeEvent) { all <- e }},
+	}
+
+	c.publish("initial", map[string]interface{}{"controls.logger.level": 3, "controls.threshold": 1})
+	for i := 2; i <= 5; i++ {
+		c.publish("update", map[string]interface{}{"controls.logger.level": 3, "controls.threshold": i})
+	}
+	c.publish("level", map[string]interface{}{"controls.logger.level": 4, "controls.threshold": 5})
+
+	for i := 2; i <= 5; i++ {
+		select {
+		case e := <-all:
+			ch, ok := e.Get("controls.threshold")
+			assert.True(t, ok)
+			assert.Equal(t, i-1, ch.Old)
+			assert.Equal(t, i, ch.New)
+		case <-time.After(time.Second):
+			t.Fatal("config event not delivered")
+		}
+	}
+
+	select {
+	case e := <-levels:
+		assert.Equal(t, "level", e.Source)
+		assert.Equal(t, []string{"controls.logger.level"}, e.Keys())
+	case <-time.After(time.Second):
+		t.Fatal("config event not delivered")
+	}
+	assert.Equal(t, 0, len(levels))
+}
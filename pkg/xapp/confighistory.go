@@ -0,0 +1,310 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
+)
+
+const (
+	ConfigHistoryURL         = "/ric/v1/config/history"
+	ConfigHistoryDiffURL     = "/ric/v1/config/history/diff"
+	ConfigHistoryVersionURL  = "/ric/v1/config/history/{version:[0-9]+}"
+	ConfigHistoryRollbackURL = "/ric/v1/config/history/{version:[0-9]+}/rollback"
+
+	configHistoryPrefix    = "history:"
+	configHistoryLatestKey = "history:latest"
+)
+
+// ConfigVersion is one applied configuration stored in the cm/<name> SDL namespace.
+// Updates are the runtime updates active at the version, on top of the config file.
+type ConfigVersion struct {
+	Version   int64                  `json:"version"`
+	Timestamp time.Time              `json:"timestamp"`
+	Source    string                 `json:"source"`
+	Hash      string                 `json:"hash"`
+	Config    map[string]interface{} `json:"config,omitempty"`
+	Updates   map[string]interface{} `json:"updates,omitempty"`
+}
+
+// -----------------------------------------------------------------------------
+// configHistory records every applied configuration with a version number.
+// The number of versions kept is controls.config.historySize (20 by default).
+// -----------------------------------------------------------------------------
+type configHistory struct {
+	mux    sync.Mutex
+	latest int64
+	hash   string
+	loaded bool
+}
+
+var configVersions = &configHistory{}
+
+// ConfigHistory returns the recorded versions without their content, oldest first
+func (*Configurator) ConfigHistory() ([]ConfigVersion, error) {
+	return configVersions.list()
+}
+
+// ConfigVersion returns a recorded version with its content
+func (*Configurator) ConfigVersion(version int64) (*ConfigVersion, error) {
+	return configVersions.get(version)
+}
+
+// Rollback restores the runtime updates active at a recorded version, it is recorded
+// as a new version. The current config file, environment and flags stay in effect.
+// The config change listeners are called as usual.
+func (*Configurator) Rollback(version int64) (*ConfigVersion, error) {
+	v, err := configVersions.get(version)
+	if err != nil {
+		return nil, err
+	}
+
+	source := fmt.Sprintf("rollback:%d", version)
+	if err := configStatus.replace(source, v.Updates); err != nil {
+		return nil, err
+	}
+	Logger.Info("Config rolled back to version %d", version)
+	configChanged(source)
+
+	return configVersions.get(configVersions.current())
+}
+
+// record stores the running config as a new version, unless it is unchanged
+func (h *configHistory) record(source string) {
+	if SdlStorage == nil {
+		return
+	}
+
+	body, _ := ioutil.ReadFile(viper.ConfigFileUsed())
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(liveConfigDocument(body)), &doc); err != nil {
+		doc = redactedConfig()
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		Logger.Error("Recording config version failed: %v", err)
+		return
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if err := h.load(); err != nil {
+		Logger.Error("Reading config history failed: %v", err)
+		return
+	}
+	if hash == h.hash {
+		return
+	}
+
+	v := ConfigVersion{Version: h.latest + 1, Timestamp: time.Now(), Source: source, Hash: hash, Config: doc, Updates: configStatus.getOverlay()}
+	b, _ := json.Marshal(v)
+	if err := SdlStorage.MStore(getCmSdlNs(), configHistoryKey(v.Version), string(b), configHistoryLatestKey, strconv.FormatInt(v.Version, 10)); err != nil {
+		Logger.Error("Recording config version failed: %v", err)
+		return
+	}
+	h.latest, h.hash = v.Version, hash
+	Logger.Info("Config version %d recorded, source=%s", v.Version, source)
+
+	size := int64(viper.GetInt("controls.config.historySize"))
+	if size <= 0 {
+		size = 20
+	}
+	if old := v.Version - size; old > 0 {
+		SdlStorage.Delete(getCmSdlNs(), []string{configHistoryKey(old)})
+	}
+}
+
+// load reads the latest version number from SDL, e.g. after a restart. h.mux must be held
+func (h *configHistory) load() error {
+	if h.loaded {
+		return nil
+	}
+	m, err := SdlStorage.Read(getCmSdlNs(), configHistoryLatestKey)
+	if err != nil {
+		return err
+	}
+	if s := sdlString(m[configHistoryLatestKey]); s != "" {
+		if h.latest, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return err
+		}
+		if v, err := h.read(h.latest); err == nil {
+			h.hash = v.Hash
+		}
+	}
+	h.loaded = true
+	return nil
+}
+
+func (h *configHistory) current() int64 {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	return h.latest
+}
+
+func (h *configHistory) get(version int64) (*ConfigVersion, error) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	return h.read(version)
+}
+
+func (h *configHistory) read(version int64) (*ConfigVersion, error) {
+	if SdlStorage == nil {
+		return nil, fmt.Errorf("SDL not available")
+	}
+	key := configHistoryKey(version)
+	m, err := SdlStorage.Read(getCmSdlNs(), key)
+	if err != nil {
+		return nil, err
+	}
+	s := sdlString(m[key])
+	if s == "" {
+		return nil, fmt.Errorf("config version %d not found", version)
+	}
+
+	v := &ConfigVersion{}
+	if err := json.Unmarshal([]byte(s), v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (h *configHistory) list() ([]ConfigVersion, error) {
+	if SdlStorage == nil {
+		return nil, fmt.Errorf("SDL not available")
+	}
+	keys, err := SdlStorage.ReadAllKeys(getCmSdlNs())
+	if err != nil {
+		return nil, err
+	}
+
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	l := []ConfigVersion{}
+	for _, k := range keys {
+		if !strings.HasPrefix(k, configHistoryPrefix) || k == configHistoryLatestKey {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimPrefix(k, configHistoryPrefix), 10, 64)
+		if err != nil {
+			continue
+		}
+		if v, err := h.read(n); err == nil {
+			v.Config, v.Updates = nil, nil
+			l = append(l, *v)
+		}
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].Version < l[j].Version })
+	return l, nil
+}
+
+// DiffConfigVersions returns the changes from version "from" to version "to"
+func (*Configurator) DiffConfigVersions(from, to int64) ([]ConfigChange, error) {
+	a, err := configVersions.get(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := configVersions.get(to)
+	if err != nil {
+		return nil, err
+	}
+	return diffConfig(flattenConfig(a.Config), flattenConfig(b.Config)), nil
+}
+
+func configHistoryKey(version int64) string {
+	return configHistoryPrefix + strconv.FormatInt(version, 10)
+}
+
+func sdlString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	}
+	return ""
+}
+
+func configHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	l, err := Config.ConfigHistory()
+	if err != nil {
+		respondWithError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, l)
+}
+
+func configVersionHandler(w http.ResponseWriter, r *http.Request) {
+	version, _ := strconv.ParseInt(mux.Vars(r)["version"], 10, 64)
+	v, err := Config.ConfigVersion(version)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, v)
+}
+
+func configDiffHandler(w http.ResponseWriter, r *http.Request) {
+	from, err1 := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+	to, err2 := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+	if err1 != nil || err2 != nil {
+		respondWithError(w, http.StatusBadRequest, "query parameters 'from' and 'to' must be version numbers")
+		return
+	}
+
+	changes, err := Config.DiffConfigVersions(from, to)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, changes)
+}
+
+func configRollbackHandler(w http.ResponseWriter, r *http.Request) {
+	version, _ := strconv.ParseInt(mux.Vars(r)["version"], 10, 64)
+	if _, err := configVersions.get(version); err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	v, err := Config.Rollback(version)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	v.Config, v.Updates = nil, nil
+	respondWithJSON(w, http.StatusOK, v)
+}
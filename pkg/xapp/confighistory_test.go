@@ -0,0 +1,114 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigHistoryAndRollback(t *testing.T) {
+	isolateConfig(t)
+	assert.Nil(t, Config.ApplyUpdate("test", []byte(`{"controls": {"testHistory": {"value": 1}}}`)))
+	first := configVersions.current()
+	assert.Nil(t, Config.ApplyUpdate("test", []byte(`{"controls": {"testHistory": {"value": 2}}}`)))
+	second := configVersions.current()
+	assert.Equal(t, first+1, second)
+
+	// Unchanged config is not recorded again
+	assert.Nil(t, Config.ApplyUpdate("test", []byte(`{"controls": {"testHistory": {"value": 2}}}`)))
+	assert.Equal(t, second, configVersions.current())
+
+	req, _ := http.NewRequest("GET", ConfigHistoryURL, nil)
+	response := executeRequest(req, nil)
+	checkResponseCode(t, http.StatusOK, response.Code)
+	var l []ConfigVersion
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&l))
+	assert.Equal(t, second, l[len(l)-1].Version)
+	assert.Equal(t, "test", l[len(l)-1].Source)
+	assert.NotEqual(t, "", l[len(l)-1].Hash)
+	assert.Nil(t, l[len(l)-1].Config)
+
+	req, _ = http.NewRequest("GET", fmt.Sprintf("%s?from=%d&to=%d", ConfigHistoryDiffURL, first, second), nil)
+	response = executeRequest(req, nil)
+	checkResponseCode(t, http.StatusOK, response.Code)
+	var changes []ConfigChange
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&changes))
+	assert.Equal(t, 1, len(changes))
+	assert.Equal(t, "controls.testhistory.value", changes[0].Key)
+	assert.Equal(t, ConfigKeyModified, changes[0].Type)
+
+	req, _ = http.NewRequest("POST", fmt.Sprintf("%s/%d/rollback", ConfigHistoryURL, first), nil)
+	response = executeRequest(req, nil)
+	checkResponseCode(t, http.StatusOK, response.Code)
+	var v ConfigVersion
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&v))
+	assert.Equal(t, second+1, v.Version)
+	assert.Equal(t, fmt.Sprintf("rollback:%d", first), v.Source)
+	assert.Equal(t, 1, viper.GetInt("controls.testhistory.value"))
+
+	req, _ = http.NewRequest("GET", fmt.Sprintf("%s/%d", ConfigHistoryURL, 99999), nil)
+	response = executeRequest(req, nil)
+	checkResponseCode(t, http.StatusNotFound, response.Code)
+
+	req, _ = http.NewRequest("GET", ConfigHistoryDiffURL+"?from=x", nil)
+	response = executeRequest(req, nil)
+	checkResponseCode(t, http.StatusBadRequest, response.Code)
+}
+
+func TestConfigRollbackKeepsFileEdits(t *testing.T) {
+	isolateConfig(t)
+
+	// Edit a copy of the config file
+	cfgFile := viper.ConfigFileUsed()
+	orig, err := ioutil.ReadFile(cfgFile)
+	assert.Nil(t, err)
+	edited := filepath.Join(t.TempDir(), filepath.Base(cfgFile))
+	assert.Nil(t, ioutil.WriteFile(edited, orig, 0644))
+	viper.SetConfigFile(edited)
+	defer viper.SetConfigFile(cfgFile)
+
+	assert.Nil(t, Config.ApplyUpdate("test", []byte(`{"controls": {"testRollback": 1}}`)))
+	version := configVersions.current()
+	assert.Nil(t, Config.ApplyUpdate("test", []byte(`{"controls": {"testRollback": 2}}`)))
+
+	_, err = Config.Rollback(version)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, viper.GetInt("controls.testrollback"))
+
+	var doc map[string]interface{}
+	assert.Nil(t, json.Unmarshal(orig, &doc))
+	doc["controls"].(map[string]interface{})["logger"] = map[string]interface{}{"level": 4}
+	data, _ := json.Marshal(doc)
+	assert.Nil(t, ioutil.WriteFile(edited, data, 0644))
+	assert.Nil(t, viper.ReadInConfig())
+	assert.True(t, configStatus.reload(edited))
+
+	// The edit is applied, the restored runtime update is kept
+	assert.Equal(t, 4, viper.GetInt("controls.logger.level"))
+	assert.Equal(t, 1, viper.GetInt("controls.testrollback"))
+}
@@ -0,0 +1,375 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// The config is built from the following sources, a later one overrides an earlier one:
+//
+//  1. defaults, set with Configurator.SetDefault
+//  2. the descriptor config file, -f or CFG_FILE
+//  3. overlay files, -config-overlays or CFG_OVERLAYS, comma separated, in the given order
+//  4. environment variables XAPP_<KEY_PATH>, e.g. XAPP_CONTROLS_LOGGER_LEVEL=4
+//  5. command-line flags, -config-set controls.logger.level=4, may be repeated
+//  6. runtime updates, CM_UPDATE pushes and PUT ConfigKeyURL
+//
+// The overlay files are read at startup. Environment and flag values are parsed as
+// JSON if possible, otherwise used as strings. A string value file:///path is replaced by the content of the file,
+// e.g. a mounted secret, without the trailing newline.
+const (
+	ConfigEnvPrefix    = "XAPP_"
+	ConfigSecretPrefix = "file://"
+)
+
+// Sources of a config key reported by Configurator.Source
+const (
+	ConfigSourceDefault = "default"
+	ConfigSourceFile    = "file"
+	ConfigSourceOverlay = "overlay"
+	ConfigSourceEnv     = "env"
+	ConfigSourceFlag    = "flag"
+	ConfigSourceUpdate  = "update"
+)
+
+// configRedactedValue is logged instead of the new value of a secret key
+const configRedactedValue = "<redacted>"
+
+type configSecret struct {
+	ref   string
+	value string
+}
+
+// configSetFlags collects the repeatable -config-set flag
+type configSetFlags []string
+
+func (s *configSetFlags) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *configSetFlags) Set(v string) error {
+	if !strings.Contains(v, "=") {
+		return fmt.Errorf("expected key=value, got '%s'", v)
+	}
+	*s = append(*s, v)
+	return nil
+}
+
+// SetDefault sets the default value of a key, used if no other source sets it
+func (*Configurator) SetDefault(key string, value interface{}) {
+	viper.SetDefault(key, value)
+}
+
+// Source returns the source of the effective value of a key, e.g. "file:/opt/ric/config/config-file.json"
+// or "env:XAPP_CONTROLS_LOGGER_LEVEL". An empty string is returned for an unknown key.
+func (*Configurator) Source(key string) string {
+	key = strings.ToLower(key)
+	return configStatus.sources([]string{key})[key]
+}
+
+// Sources returns the source of every key of the running config
+func (*Configurator) Sources() map[string]string {
+	var keys []string
+	for key := range flattenConfig(viper.AllSettings()) {
+		keys = append(keys, key)
+	}
+	return configStatus.sources(keys)
+}
+
+// initLayers reads the overlay files, the environment and the -config-set flags. c.mux must be held
+func (c *configState) initLayers(overlays, sets []string) {
+	c.files = nil
+	c.fileLayers = nil
+	for _, f := range overlays {
+		if f = strings.TrimSpace(f); f == "" {
+			continue
+		}
+		m, err := readConfigFile(f)
+		if err != nil {
+			c.log.Error("Reading config overlay file %s failed: %v", f, err)
+			continue
+		}
+		c.log.Info("Using config overlay file: %s", f)
+		c.files = append(c.files, f)
+		c.fileLayers = append(c.fileLayers, m)
+	}
+
+	known := viper.AllKeys()
+	for _, m := range c.fileLayers {
+		for k := range flattenConfig(m) {
+			known = append(known, k)
+		}
+	}
+	envKeys := configEnvKeys(known)
+
+	c.env = make(map[string]interface{})
+	c.envNames = make(map[string]string)
+	for _, e := range os.Environ() {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) != 2 || !strings.HasPrefix(kv[0], ConfigEnvPrefix) || kv[0] == ConfigEnvPrefix {
+			continue
+		}
+		key := configEnvKey(strings.TrimPrefix(kv[0], ConfigEnvPrefix), envKeys)
+		c.env = mergeConfigMaps(c.env, configKeyMap(key, parseConfigValue(kv[1])))
+		c.envNames[key] = kv[0]
+	}
+
+	c.flags = make(map[string]interface{})
+	for _, s := range sets {
+		kv := strings.SplitN(s, "=", 2)
+		key := strings.ToLower(strings.TrimSpace(kv[0]))
+		c.flags = mergeConfigMaps(c.flags, configKeyMap(key, parseConfigValue(kv[1])))
+	}
+}
+
+// layers returns the overlay files, environment, flags and runtime updates merged. c.mux must be held
+func (c *configState) layers() map[string]interface{} {
+	m := map[string]interface{}{}
+	for _, overlay := range c.fileLayers {
+		m = mergeConfigMaps(m, overlay)
+	}
+	m = mergeConfigMaps(m, c.env)
+	m = mergeConfigMaps(m, c.flags)
+	if c.overlay != nil {
+		m = mergeConfigMaps(m, lowercaseConfigMap(c.overlay))
+	}
+	return m
+}
+
+// effective returns the config with all the sources merged and the secrets resolved. c.mux must be held
+func (c *configState) effective() map[string]interface{} {
+	return c.resolveSecrets(mergeConfigMaps(viper.AllSettings(), c.layers()))
+}
+
+// mergeLayers merges the other sources into the config read by viper. c.mux must be held
+func (c *configState) mergeLayers() {
+	m := c.effective()
+
+	// A secret already merged into viper keeps its reference until the value changes
+	flat := flattenConfig(m)
+	for key, s := range c.secrets {
+		if v, ok := flat[key].(string); !ok || v != s.value {
+			delete(c.secrets, key)
+		}
+	}
+
+	if err := viper.MergeConfigMap(m); err != nil && c.log != nil {
+		c.log.Error("Merging config sources failed: %v", err)
+	}
+}
+
+// resolveSecrets replaces the file:// values with the file content. c.mux must be held
+func (c *configState) resolveSecrets(m map[string]interface{}) map[string]interface{} {
+	if c.secrets == nil {
+		c.secrets = make(map[string]configSecret)
+	}
+
+	for key, v := range flattenConfig(m) {
+		ref, ok := v.(string)
+		if !ok || !strings.HasPrefix(ref, ConfigSecretPrefix) {
+			continue
+		}
+		data, err := ioutil.ReadFile(strings.TrimPrefix(ref, ConfigSecretPrefix))
+		if err != nil {
+			if c.log != nil {
+				c.log.Error("Reading secret of config key '%s' failed: %v", key, err)
+			}
+			continue
+		}
+		value := strings.TrimRight(string(data), "\r\n")
+		c.secrets[key] = configSecret{ref: ref, value: value}
+		m = mergeConfigMaps(m, configKeyMap(key, value))
+	}
+	return m
+}
+
+// redactSecrets returns the value of a key, or a subtree of keys, with the values resolved
+// from secret files replaced by their file:// reference
+func (c *configState) redactSecrets(key string, v interface{}) interface{} {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.redact(strings.ToLower(key), v)
+}
+
+// redact is redactSecrets with c.mux held. The maps holding secrets are copied.
+func (c *configState) redact(key string, v interface{}) interface{} {
+	if s, ok := c.secrets[key]; ok {
+		return s.ref
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok || !c.hasSecret(key) {
+		return v
+	}
+
+	redacted := make(map[string]interface{}, len(m))
+	for k, sub := range m {
+		path := strings.ToLower(k)
+		if key != "" {
+			path = key + "." + path
+		}
+		redacted[k] = c.redact(path, sub)
+	}
+	return redacted
+}
+
+// isSecret tells if the key, or a key under it, is resolved from a secret file
+func (c *configState) isSecret(key string) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.hasSecret(strings.ToLower(key))
+}
+
+// hasSecret tells if the key, or a key under it, is resolved from a secret file. c.mux must be held
+func (c *configState) hasSecret(key string) bool {
+	for k := range c.secrets {
+		if key == "" || k == key || strings.HasPrefix(k, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// configSourceLayer is a config source with its keys flattened
+type configSourceLayer struct {
+	source string
+	keys   map[string]interface{}
+}
+
+// has tells if the key, or a key under it, is set in the layer
+func (l configSourceLayer) has(key string) bool {
+	if _, ok := l.keys[key]; ok {
+		return true
+	}
+	for k := range l.keys {
+		if strings.HasPrefix(k, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceLayers returns the config sources, the highest priority first. c.mux must be held
+func (c *configState) sourceLayers() []configSourceLayer {
+	var l []configSourceLayer
+	if c.overlay != nil {
+		l = append(l, configSourceLayer{ConfigSourceUpdate, flattenConfig(lowercaseConfigMap(c.overlay))})
+	}
+	l = append(l, configSourceLayer{ConfigSourceFlag, flattenConfig(c.flags)})
+
+	env := flattenConfig(c.env)
+	var envKeys []string
+	for key := range c.envNames {
+		envKeys = append(envKeys, key)
+	}
+	sort.Strings(envKeys)
+	for _, key := range envKeys {
+		keys := make(map[string]interface{})
+		for k, v := range env {
+			if k == key || strings.HasPrefix(k, key+".") {
+				keys[k] = v
+			}
+		}
+		l = append(l, configSourceLayer{ConfigSourceEnv + ":" + c.envNames[key], keys})
+	}
+
+	for i := len(c.files) - 1; i >= 0; i-- {
+		l = append(l, configSourceLayer{ConfigSourceOverlay + ":" + c.files[i], flattenConfig(c.fileLayers[i])})
+	}
+	if cfgFile := viper.ConfigFileUsed(); cfgFile != "" {
+		if m, err := readConfigFile(cfgFile); err == nil {
+			l = append(l, configSourceLayer{ConfigSourceFile + ":" + cfgFile, flattenConfig(m)})
+		}
+	}
+	return l
+}
+
+// sources returns the sources of the keys, the unknown keys are left out
+func (c *configState) sources(keys []string) map[string]string {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	layers := c.sourceLayers()
+	sources := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if !viper.IsSet(key) {
+			continue
+		}
+		sources[key] = ConfigSourceDefault
+		for _, l := range layers {
+			if l.has(key) {
+				sources[key] = l.source
+				break
+			}
+		}
+	}
+	return sources
+}
+
+// readConfigFile reads a config file of any type supported by viper, keys lowercased
+func readConfigFile(f string) (map[string]interface{}, error) {
+	v := viper.New()
+	v.SetConfigFile(f)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	return v.AllSettings(), nil
+}
+
+// configEnvKeys maps the environment variable names, without the prefix, to the known
+// key paths. If several keys have the same name, the first one in sort order is used.
+func configEnvKeys(known []string) map[string]string {
+	sorted := append([]string{}, known...)
+	sort.Strings(sorted)
+
+	keys := make(map[string]string, len(sorted))
+	for _, k := range sorted {
+		name := strings.ToUpper(strings.Replace(k, ".", "_", -1))
+		if _, ok := keys[name]; !ok {
+			keys[name] = k
+		}
+	}
+	return keys
+}
+
+// configEnvKey maps the name of an environment variable without the prefix to a key
+// path. A known key matches with the dots replaced by underscores, otherwise every
+// underscore is a dot, e.g. CONTROLS_LOGGER_LEVEL is controls.logger.level.
+func configEnvKey(name string, known map[string]string) string {
+	if k, ok := known[name]; ok {
+		return k
+	}
+	return strings.ToLower(strings.Replace(name, "_", ".", -1))
+}
+
+func parseConfigValue(s string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	return v
+}
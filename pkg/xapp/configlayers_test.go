@@ -0,0 +1,125 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func setConfigLayers(overlays, sets []string) {
+	configStatus.mux.Lock()
+	defer configStatus.mux.Unlock()
+
+	viper.ReadInConfig()
+	configStatus.initLayers(overlays, sets)
+	configStatus.mergeLayers()
+}
+
+func TestConfigLayers(t *testing.T) {
+	isolateConfig(t)
+	dir := t.TempDir()
+
+	overlay := filepath.Join(dir, "overlay.yaml")
+	secret := filepath.Join(dir, "password")
+	ioutil.WriteFile(overlay, []byte("controls:\n  layertest:\n    a: overlay\n    b: overlay\n    c: overlay\n    password: file://"+secret+"\n"), 0644)
+	ioutil.WriteFile(secret, []byte("s3cret\n"), 0600)
+
+	t.Setenv("XAPP_CONTROLS_LAYERTEST_B", "env")
+	t.Setenv("XAPP_CONTROLS_LAYERTEST_C", "env")
+	t.Setenv("XAPP_CONTROLS_LAYERTEST_NUM", "42")
+	setConfigLayers([]string{overlay}, []string{"controls.layertest.c=flag"})
+
+	assert.Equal(t, "overlay", viper.GetString("controls.layertest.a"))
+	assert.Equal(t, "env", viper.GetString("controls.layertest.b"))
+	assert.Equal(t, "flag", viper.GetString("controls.layertest.c"))
+	assert.Equal(t, 42, viper.GetInt("controls.layertest.num"))
+	assert.Equal(t, "s3cret", viper.GetString("controls.layertest.password"))
+
+	assert.Equal(t, "overlay:"+overlay, Config.Source("controls.layertest.a"))
+	assert.Equal(t, "env:XAPP_CONTROLS_LAYERTEST_B", Config.Source("controls.layertest.b"))
+	assert.Equal(t, ConfigSourceFlag, Config.Source("controls.layertest.c"))
+	assert.Equal(t, "file:"+viper.ConfigFileUsed(), Config.Source("controls.logger.level"))
+	assert.Equal(t, "", Config.Source("controls.nosuchkey"))
+	assert.Equal(t, ConfigSourceFlag, Config.Sources()["controls.layertest.c"])
+
+	Config.SetDefault("controls.layertest.d", "default")
+	assert.Equal(t, ConfigSourceDefault, Config.Source("controls.layertest.d"))
+
+	// The secret is resolved, but not shown
+	req, _ := http.NewRequest("GET", "/ric/v1/config/controls.layertest.password", nil)
+	response := executeRequest(req, nil)
+	checkResponseCode(t, http.StatusOK, response.Code)
+	var v ConfigKeyValue
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&v))
+	assert.Equal(t, "file://"+secret, v.Value)
+	assert.Equal(t, "overlay:"+overlay, v.Source)
+
+	// Also in the subtree holding it
+	req, _ = http.NewRequest("GET", "/ric/v1/config/controls", nil)
+	response = executeRequest(req, nil)
+	checkResponseCode(t, http.StatusOK, response.Code)
+	assert.False(t, strings.Contains(response.Body.String(), "s3cret"))
+	assert.True(t, strings.Contains(response.Body.String(), "file://"+secret))
+	assert.Equal(t, "s3cret", viper.GetString("controls.layertest.password"))
+
+	// And in the config documents dumped and recorded into the history
+	assert.Equal(t, "file://"+secret, flattenConfig(redactedConfig())["controls.layertest.password"])
+
+	// A new value of a secret key is not written to the audit log
+	req, _ = http.NewRequest("PUT", "/ric/v1/config/controls.layertest.password?dryRun=true", strings.NewReader(`{"value": "n3w"}`))
+	response = executeRequest(req, nil)
+	checkResponseCode(t, http.StatusOK, response.Code)
+	var update ConfigKeyUpdate
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&update))
+	assert.Equal(t, "file://"+secret, update.Old)
+
+	// The layers are kept over a reload of the config file
+	configStatus.mux.Lock()
+	viper.ReadInConfig()
+	configStatus.mergeLayers()
+	configStatus.mux.Unlock()
+	assert.Equal(t, "flag", viper.GetString("controls.layertest.c"))
+	assert.Equal(t, "s3cret", viper.GetString("controls.layertest.password"))
+}
+
+func TestConfigEnvKey(t *testing.T) {
+	known := configEnvKeys([]string{"controls.logger.level", "rmr.maxsize", "controls.some_key"})
+	assert.Equal(t, "controls.logger.level", configEnvKey("CONTROLS_LOGGER_LEVEL", known))
+	assert.Equal(t, "controls.some_key", configEnvKey("CONTROLS_SOME_KEY", known))
+	assert.Equal(t, "controls.new.key", configEnvKey("CONTROLS_NEW_KEY", known))
+	assert.Equal(t, "controls.some.key", configEnvKey("CONTROLS_SOME_KEY", configEnvKeys([]string{"controls.some_key", "controls.some.key"})))
+
+	assert.Equal(t, float64(1), parseConfigValue("1"))
+	assert.Equal(t, true, parseConfigValue("true"))
+	assert.Equal(t, "text", parseConfigValue("text"))
+
+	var sets configSetFlags
+	assert.Nil(t, sets.Set("a.b=1"))
+	assert.NotNil(t, sets.Set("a.b"))
+	assert.True(t, strings.Contains(sets.String(), "a.b=1"))
+}
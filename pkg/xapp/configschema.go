@@ -0,0 +1,439 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+	"github.com/spf13/viper"
+)
+
+// ConfigSchemaSection is the part of the configuration described by the descriptor's schema
+const ConfigSchemaSection = "controls"
+
+var ConfigCounterOpts = []CounterOpts{
+	{Name: "ConfigValidationFailures", Help: "The total number of configurations rejected by the schema validation"},
+}
+
+type ConfigValidationFailureCB func(filename string, err error)
+
+type configBinding struct {
+	key string
+	out interface{}
+}
+
+// -----------------------------------------------------------------------------
+// configState validates the configuration against the descriptor's JSON schema
+// and keeps the structs bound with Configurator.Bind up to date. A reloaded
+// config failing the validation is replaced with the last valid one.
+// -----------------------------------------------------------------------------
+type configState struct {
+	mux        sync.Mutex
+	log        *Log
+	schema     *spec.Schema
+	bindings   []configBinding
+	lastValid  []byte
+	lastErr    error
+	overlay    map[string]interface{}   // Merged runtime updates, keys as first received
+	files      []string                 // Overlay config files
+	fileLayers []map[string]interface{} // Content of the overlay files
+	env        map[string]interface{}   // XAPP_ environment variables
+	envNames   map[string]string        // Key path to environment variable
+	flags      map[string]interface{}   // -config-set command-line flags
+	secrets    map[string]configSecret  // Keys resolved from file:// references
+	failureCBs []ConfigValidationFailureCB
+	failures   int
+	stat       Counter
+}
+
+var configStatus = &configState{}
+
+// AddConfigValidationFailureListener registers a callback called when a config is rejected
+func AddConfigValidationFailureListener(f ConfigValidationFailureCB) {
+	configStatus.mux.Lock()
+	defer configStatus.mux.Unlock()
+	configStatus.failureCBs = append(configStatus.failureCBs, f)
+}
+
+// Bind unmarshals the config under key ("" for all) into out using its json tags, and
+// again on every valid reload before the config change listeners are called.
+// An error is returned if the current config is not valid, out is then left untouched.
+func (*Configurator) Bind(key string, out interface{}) error {
+	return configStatus.bind(key, out)
+}
+
+// SetSchema replaces the JSON schema used for validating the config, nil disables the validation
+func (*Configurator) SetSchema(schema []byte) error {
+	s, err := parseConfigSchema(schema)
+	if err != nil {
+		return err
+	}
+	configStatus.mux.Lock()
+	defer configStatus.mux.Unlock()
+	configStatus.schema = s
+	return nil
+}
+
+// Validate validates the current config against the schema
+func (*Configurator) Validate() error {
+	configStatus.mux.Lock()
+	defer configStatus.mux.Unlock()
+	return configStatus.validate(configStatus.effective())
+}
+
+// configSchemaFile returns CFG_SCHEMA, or schema.json next to the config file if it exists
+func configSchemaFile(cfgFile string) string {
+	if f := os.Getenv("CFG_SCHEMA"); f != "" {
+		return f
+	}
+	if cfgFile == "" {
+		return ""
+	}
+	f := filepath.Join(filepath.Dir(cfgFile), "schema.json")
+	if _, err := os.Stat(f); err != nil {
+		return ""
+	}
+	return f
+}
+
+// parseConfigSchema parses the schema. Viper lowercases the config keys, so the
+// property names of the schema are lowercased too.
+func parseConfigSchema(data []byte) (*spec.Schema, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid config schema: %v", err)
+	}
+	b, _ := json.Marshal(lowercaseSchema(raw))
+
+	s := &spec.Schema{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("invalid config schema: %v", err)
+	}
+	return s, nil
+}
+
+func lowercaseSchema(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			switch k {
+			case "properties":
+				if props, ok := e.(map[string]interface{}); ok {
+					lower := make(map[string]interface{}, len(props))
+					for name, p := range props {
+						lower[strings.ToLower(name)] = lowercaseSchema(p)
+					}
+					t[k] = lower
+					continue
+				}
+			case "required":
+				if names, ok := e.([]interface{}); ok {
+					for i, n := range names {
+						if s, ok := n.(string); ok {
+							names[i] = strings.ToLower(s)
+						}
+					}
+					continue
+				}
+			}
+			t[k] = lowercaseSchema(e)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = lowercaseSchema(e)
+		}
+	}
+	return v
+}
+
+// load reads the schema and the other config sources, and validates the initial config
+func (c *configState) load(l *Log, cfgFile string, overlays, sets []string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.log = l
+	c.initLayers(overlays, sets)
+	c.mergeLayers()
+
+	if f := configSchemaFile(cfgFile); f != "" {
+		data, err := ioutil.ReadFile(f)
+		if err == nil {
+			c.schema, err = parseConfigSchema(data)
+		}
+		if err != nil {
+			l.Error("Reading config schema %s failed: %v", f, err)
+		} else {
+			l.Info("Using config schema: %s", f)
+		}
+	}
+
+	if err := c.validate(c.effective()); err != nil {
+		c.reject(cfgFile, err)
+		return
+	}
+	c.lastValid, _ = ioutil.ReadFile(cfgFile)
+}
+
+// reload is called after viper has re-read the config file, it returns false if
+// the new config was rejected and the last valid one restored
+func (c *configState) reload(cfgFile string) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if err := c.validate(c.effective()); err != nil {
+		if c.lastValid != nil {
+			viper.SetConfigType(strings.TrimPrefix(filepath.Ext(cfgFile), "."))
+			if err := viper.ReadConfig(bytes.NewReader(c.lastValid)); err != nil {
+				c.log.Error("Restoring last valid config failed: %v", err)
+			}
+		}
+		c.mergeLayers()
+		c.reject(cfgFile, err)
+		return false
+	}
+
+	c.lastValid, _ = ioutil.ReadFile(cfgFile)
+	c.lastErr = nil
+	c.mergeLayers()
+	c.rebind()
+	return true
+}
+
+// apply validates an update and merges it into the running config, the update
+// is kept over later reloads of the config file
+func (c *configState) apply(source string, update map[string]interface{}) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	candidate := c.resolveSecrets(mergeConfigMaps(c.effective(), lowercaseConfigMap(update)))
+	if err := c.validate(candidate); err != nil {
+		c.reject(source, err)
+		return err
+	}
+
+	if c.overlay == nil {
+		c.overlay = make(map[string]interface{})
+	}
+	c.overlay = mergeConfigMapsFold(c.overlay, update)
+	c.lastErr = nil
+	c.mergeLayers()
+	c.rebind()
+	return nil
+}
+
+// check validates an update without applying it or reporting a failure
+func (c *configState) check(update map[string]interface{}) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.validate(c.resolveSecrets(mergeConfigMaps(c.effective(), lowercaseConfigMap(update))))
+}
+
+// replace replaces the runtime updates with overlay, e.g. the ones active at an earlier
+// config version. The config file and the other sources are applied as usual.
+func (c *configState) replace(source string, overlay map[string]interface{}) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	// The config file without the earlier updates
+	if cfgFile := viper.ConfigFileUsed(); c.lastValid != nil && cfgFile != "" {
+		viper.SetConfigType(strings.TrimPrefix(filepath.Ext(cfgFile), "."))
+		if err := viper.ReadConfig(bytes.NewReader(c.lastValid)); err != nil {
+			c.log.Error("Re-reading config file failed: %v", err)
+		}
+	}
+
+	previous := c.overlay
+	c.overlay = nil
+	if overlay != nil {
+		c.overlay = mergeConfigMaps(map[string]interface{}{}, overlay)
+	}
+	if err := c.validate(c.effective()); err != nil {
+		c.overlay = previous
+		c.mergeLayers()
+		c.reject(source, err)
+		return err
+	}
+
+	c.lastErr = nil
+	c.mergeLayers()
+	c.rebind()
+	return nil
+}
+
+// getOverlay returns a copy of the merged updates, nil if none
+func (c *configState) getOverlay() map[string]interface{} {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.overlay == nil {
+		return nil
+	}
+	return mergeConfigMaps(map[string]interface{}{}, c.overlay)
+}
+
+func (c *configState) rebind() {
+	for _, b := range c.bindings {
+		if err := unmarshalConfig(b.key, b.out); err != nil {
+			c.log.Error("Config binding of '%s' failed: %v", b.key, err)
+		}
+	}
+}
+
+func (c *configState) bind(key string, out interface{}) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.bindings = append(c.bindings, configBinding{key, out})
+	if err := c.validate(c.effective()); err != nil {
+		return err
+	}
+	return unmarshalConfig(key, out)
+}
+
+func (c *configState) validate(settings map[string]interface{}) error {
+	if c.schema == nil {
+		return nil
+	}
+
+	// Normalize the types, e.g. integers of a yaml config
+	var data interface{}
+	b, err := json.Marshal(settings[ConfigSchemaSection])
+	if err == nil {
+		err = json.Unmarshal(b, &data)
+	}
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	return validate.AgainstSchema(c.schema, data, strfmt.Default)
+}
+
+// reject reports the failure, c.mux must be held
+func (c *configState) reject(cfgFile string, err error) {
+	c.failures++
+	c.lastErr = err
+	if c.log != nil {
+		c.log.Error("Config %s rejected: %v", cfgFile, err)
+	}
+	if c.stat != nil {
+		c.stat.Inc()
+	}
+	for _, f := range c.failureCBs {
+		go f(cfgFile, err)
+	}
+}
+
+// registerMetrics registers the config counters, including failures seen before Metric existed
+func (c *configState) registerMetrics() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.stat = Metric.RegisterCounterGroup(ConfigCounterOpts, "Config")["ConfigValidationFailures"]
+	if c.failures > 0 {
+		c.stat.Add(float64(c.failures))
+	}
+}
+
+func unmarshalConfig(key string, out interface{}) error {
+	var v interface{}
+	if key == "" {
+		v = viper.AllSettings()
+	} else {
+		v = viper.Get(key)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// mergeConfigMaps merges src into a copy of dst, nested maps are merged key by key
+func mergeConfigMaps(dst, src map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(dst))
+	for k, v := range dst {
+		out[k] = v
+	}
+	for k, v := range src {
+		sub, ok := v.(map[string]interface{})
+		if prev, isMap := out[k].(map[string]interface{}); ok && isMap {
+			out[k] = mergeConfigMaps(prev, sub)
+		} else if ok {
+			out[k] = mergeConfigMaps(map[string]interface{}{}, sub)
+		} else {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// mergeConfigMapsFold is mergeConfigMaps matching the keys case-insensitively, a key
+// already in dst keeps its case
+func mergeConfigMapsFold(dst, src map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(dst))
+	for k, v := range dst {
+		out[k] = v
+	}
+	for k, v := range src {
+		for existing := range out {
+			if strings.EqualFold(existing, k) {
+				k = existing
+				break
+			}
+		}
+		sub, ok := v.(map[string]interface{})
+		if prev, isMap := out[k].(map[string]interface{}); ok && isMap {
+			out[k] = mergeConfigMapsFold(prev, sub)
+		} else if ok {
+			out[k] = mergeConfigMapsFold(map[string]interface{}{}, sub)
+		} else {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func lowercaseConfigMap(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if sub, ok := v.(map[string]interface{}); ok {
+			v = lowercaseConfigMap(sub)
+		}
+		out[strings.ToLower(k)] = v
+	}
+	return out
+}
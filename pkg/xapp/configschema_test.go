@@ -0,0 +1,124 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type testControls struct {
+	Logger struct {
+		Level    int  `json:"level"`
+		NoFormat bool `json:"noFormat"`
+	} `json:"logger"`
+	Subscription struct {
+		Host    string `json:"host"`
+		Timeout int    `json:"timeout"`
+	} `json:"subscription"`
+}
+
+func testConfigSchema(maxLevel int) []byte {
+	return []byte(fmt.Sprintf(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"required": ["logger"],
+		"properties": {
+			"logger": {
+				"type": "object",
+				"required": ["level", "noFormat"],
+				"properties": {
+					"level": {"type": "integer", "minimum": 1, "maximum": %d},
+					"noFormat": {"type": "boolean"}
+				}
+			}
+		}
+	}`, maxLevel))
+}
+
+func newTestConfigState(t *testing.T, maxLevel int) *configState {
+	s, err := parseConfigSchema(testConfigSchema(maxLevel))
+	assert.Nil(t, err)
+	lastValid, _ := ioutil.ReadFile(viper.ConfigFileUsed())
+	return &configState{log: Logger, schema: s, lastValid: lastValid}
+}
+
+func TestConfigBind(t *testing.T) {
+	c := newTestConfigState(t, 5)
+
+	var controls testControls
+	assert.Nil(t, c.bind("controls", &controls))
+	assert.Equal(t, 3, controls.Logger.Level)
+	assert.True(t, controls.Logger.NoFormat)
+	assert.Equal(t, "localhost:8088", controls.Subscription.Host)
+	assert.Equal(t, 2, controls.Subscription.Timeout)
+
+	var level int
+	assert.Nil(t, c.bind("controls.logger.level", &level))
+	assert.Equal(t, 3, level)
+}
+
+func TestConfigBindInvalid(t *testing.T) {
+	c := newTestConfigState(t, 2)
+
+	var controls testControls
+	assert.NotNil(t, c.bind("controls", &controls))
+	assert.Equal(t, 0, controls.Logger.Level)
+}
+
+func TestConfigReloadKeepsLastValid(t *testing.T) {
+	c := newTestConfigState(t, 5)
+
+	var controls testControls
+	assert.Nil(t, c.bind("controls", &controls))
+	controls.Logger.Level = 0
+
+	failures := make(chan error, 1)
+	c.failureCBs = append(c.failureCBs, func(filename string, err error) { failures <- err })
+
+	// The same config fails with a stricter schema
+	c.schema, _ = parseConfigSchema(testConfigSchema(2))
+	assert.False(t, c.reload(viper.ConfigFileUsed()))
+	assert.Equal(t, 0, controls.Logger.Level)
+	assert.Equal(t, 3, viper.GetInt("controls.logger.level"))
+	select {
+	case err := <-failures:
+		assert.NotNil(t, err)
+	case <-time.After(time.Second):
+		t.Error("validation failure not reported")
+	}
+	assert.Equal(t, 1, c.failures)
+
+	c.schema, _ = parseConfigSchema(testConfigSchema(5))
+	assert.True(t, c.reload(viper.ConfigFileUsed()))
+	assert.Equal(t, 3, controls.Logger.Level)
+}
+
+func TestConfigSchemaInvalid(t *testing.T) {
+	_, err := parseConfigSchema([]byte(`{"type": `))
+	assert.NotNil(t, err)
+
+	assert.Nil(t, Config.Validate())
+}
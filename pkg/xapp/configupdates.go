@@ -0,0 +1,114 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// EnableConfigUpdates applies the config pushed with PublishConfigChange (CM_UPDATE)
+// to the running config. The config currently stored in SDL is applied first.
+// Enabled at startup if controls.config.applyUpdates is set.
+func (c *Configurator) EnableConfigUpdates() error {
+	name := viper.GetString("name")
+
+	stored, err := ReadConfig(name)
+	if err != nil {
+		Logger.Error("Reading stored config of %s failed: %v", name, err)
+	} else if v, ok := stored[name].(string); ok && v != "" {
+		if err := c.ApplyUpdate(getCmSdlNs(), []byte(v)); err != nil {
+			Logger.Error("Applying stored config failed: %v", err)
+		}
+	}
+
+	return c.SetSDLNotificationCB(name, func(channel string, events ...string) {
+		for _, ev := range events {
+			if err := c.ApplyUpdate(channel, []byte(ev)); err != nil {
+				Logger.Error("Applying config update from %s failed: %v", channel, err)
+			}
+		}
+	})
+}
+
+// ApplyUpdate validates a config update and merges it into the running config.
+// The update is either a config document or an XAppConfig with the document in
+// "config". The config change listeners are called with source as the file name.
+func (*Configurator) ApplyUpdate(source string, data []byte) error {
+	update, err := parseConfigUpdate(data)
+	if err != nil {
+		return err
+	}
+	if err := configStatus.apply(source, update); err != nil {
+		return err
+	}
+
+	Logger.Info("Config update from %s applied", source)
+	configChanged(source)
+	return nil
+}
+
+func parseConfigUpdate(data []byte) (map[string]interface{}, error) {
+	var update map[string]interface{}
+	if err := json.Unmarshal(data, &update); err != nil {
+		return nil, fmt.Errorf("invalid config update: %v", err)
+	}
+
+	if _, ok := update["metadata"]; !ok {
+		return update, nil
+	}
+	switch cfg := update["config"].(type) {
+	case map[string]interface{}:
+		return cfg, nil
+	case string:
+		return parseConfigUpdate([]byte(cfg))
+	default:
+		return nil, fmt.Errorf("invalid config update: no config")
+	}
+}
+
+// liveConfigDocument returns the config file content with the applied updates.
+// The values resolved from secret files are shown as their file:// references.
+func liveConfigDocument(body []byte) string {
+	overlay := configStatus.getOverlay()
+	if overlay == nil {
+		return string(body)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		// Not a json file, the keys are lowercased by viper
+		doc = redactedConfig()
+		overlay = lowercaseConfigMap(overlay)
+	}
+	b, err := json.Marshal(mergeConfigMapsFold(doc, overlay))
+	if err != nil {
+		return string(body)
+	}
+	return string(b)
+}
+
+// redactedConfig returns the running config without the values resolved from secret files
+func redactedConfig() map[string]interface{} {
+	doc, _ := configStatus.redactSecrets("", viper.AllSettings()).(map[string]interface{})
+	return doc
+}
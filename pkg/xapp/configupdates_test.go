@@ -0,0 +1,108 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// isolateConfig runs t on the config file without runtime updates. The updates, the
+// other config sources and the config read from the file are restored when t ends.
+func isolateConfig(t *testing.T) {
+	c := configStatus
+	c.mux.Lock()
+	overlay, lastValid := c.overlay, c.lastValid
+	if overlay != nil {
+		overlay = mergeConfigMaps(map[string]interface{}{}, overlay)
+	}
+	files, fileLayers, env, envNames, flags := c.files, c.fileLayers, c.env, c.envNames, c.flags
+	c.mux.Unlock()
+
+	restore := func(overlay map[string]interface{}) {
+		c.mux.Lock()
+		defer c.mux.Unlock()
+
+		c.overlay, c.lastValid = overlay, lastValid
+		c.files, c.fileLayers, c.env, c.envNames, c.flags = files, fileLayers, env, envNames, flags
+		if err := viper.ReadInConfig(); err != nil {
+			t.Errorf("Re-reading config failed: %v", err)
+		}
+		c.mergeLayers()
+		c.rebind()
+	}
+	restore(nil)
+	t.Cleanup(func() { restore(overlay) })
+}
+
+func TestConfigApplyUpdate(t *testing.T) {
+	isolateConfig(t)
+	events := make(chan ConfigChangeEvent, 10)
+	AddConfigEventListener("controls.testUpdate", func(e ConfigChangeEvent) { events <- e })
+
+	assert.Nil(t, Config.ApplyUpdate("test", []byte(`{"controls": {"testUpdate": {"threshold": 5}}}`)))
+	assert.Equal(t, 5, viper.GetInt("controls.testupdate.threshold"))
+	assert.Equal(t, 3, viper.GetInt("controls.logger.level"))
+
+	select {
+	case e := <-events:
+		c, ok := e.Get("controls.testUpdate.threshold")
+		assert.True(t, ok)
+		assert.Equal(t, ConfigKeyAdded, c.Type)
+	case <-time.After(time.Second):
+		t.Fatal("config event not delivered")
+	}
+
+	// XAppConfig wrapping and rejection by the schema
+	assert.Nil(t, Config.SetSchema([]byte(`{"properties": {"testUpdate": {"properties": {"threshold": {"maximum": 10}}}}}`)))
+	defer Config.SetSchema(nil)
+
+	assert.NotNil(t, Config.ApplyUpdate("test", []byte(`{"metadata": {}, "config": "{\"controls\": {\"testUpdate\": {\"threshold\": 20}}}"}`)))
+	assert.Equal(t, 5, viper.GetInt("controls.testupdate.threshold"))
+
+	assert.Nil(t, Config.ApplyUpdate("test", []byte(`{"metadata": {}, "config": {"controls": {"testUpdate": {"threshold": 7}}}}`)))
+	assert.Equal(t, 7, viper.GetInt("controls.testupdate.threshold"))
+
+	assert.NotNil(t, Config.ApplyUpdate("test", []byte(`[1, 2]`)))
+
+	var doc map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(liveConfigDocument([]byte(`{"name": "xapp", "controls": {"logger": {"level": 3}}}`))), &doc))
+	assert.Equal(t, "xapp", doc["name"])
+	assert.Equal(t, map[string]interface{}{
+		"logger":     map[string]interface{}{"level": float64(3)},
+		"testUpdate": map[string]interface{}{"threshold": float64(7)},
+	}, doc["controls"])
+}
+
+func TestConfigEnableUpdates(t *testing.T) {
+	isolateConfig(t)
+	name := viper.GetString("name")
+	defer SdlStorage.Delete(getCmSdlNs(), []string{name})
+	assert.Nil(t, PublishConfigChange(name, `{"controls": {"testStored": 1}}`))
+	assert.Nil(t, Config.EnableConfigUpdates())
+	assert.Equal(t, 1, viper.GetInt("controls.teststored"))
+
+	assert.Nil(t, PublishConfigChange(name, `{"controls": {"testStored": 2}}`))
+	assert.Eventually(t, func() bool { return viper.GetInt("controls.teststored") == 2 }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,133 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"time"
+
+	mdclog "gerrit.o-ran-sc.org/r/com/golog"
+	"github.com/spf13/viper"
+)
+
+// DefaultLogBufferSize is the number of recent log records kept in memory, see
+// controls.logger.bufferSize
+const DefaultLogBufferSize = 1000
+
+// LogRecord is a log line kept in memory for the symptom data
+type LogRecord struct {
+	Time   time.Time              `json:"time"`
+	Level  string                 `json:"level"`
+	Msg    string                 `json:"msg"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+// logRing keeps the latest log records, a nil ring keeps nothing
+type logRing struct {
+	records []LogRecord
+	next    int
+	full    bool
+}
+
+func newLogRing(size int) *logRing {
+	if size <= 0 {
+		return nil
+	}
+	return &logRing{records: make([]LogRecord, size)}
+}
+
+// SetBufferSize sets the number of log records kept in memory, 0 disables the buffer.
+// The records kept so far are dropped.
+func (l *Log) SetBufferSize(size int) {
+	l.output.mux.Lock()
+	defer l.output.mux.Unlock()
+
+	if r := l.output.records; r != nil && len(r.records) == size {
+		return
+	}
+	l.output.records = newLogRing(size)
+}
+
+// Records returns the log records kept in memory logged between from and to, oldest
+// first. A zero from or to is not limiting.
+func (l *Log) Records(from, to time.Time) []LogRecord {
+	l.output.mux.Lock()
+	defer l.output.mux.Unlock()
+	return l.output.records.between(from, to)
+}
+
+// add stores a record, the output mutex must be held
+func (r *logRing) add(t time.Time, level mdclog.Level, msg string, fields []logField) {
+	if r == nil {
+		return
+	}
+
+	rec := LogRecord{Time: t, Level: logLevelName(level), Msg: msg}
+	if len(fields) > 0 {
+		rec.Fields = make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			rec.Fields[f.key] = jsonLogValue(f.value)
+		}
+	}
+	r.records[r.next] = rec
+	r.next = (r.next + 1) % len(r.records)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *logRing) between(from, to time.Time) []LogRecord {
+	l := []LogRecord{}
+	if r == nil {
+		return l
+	}
+
+	start, n := 0, r.next
+	if r.full {
+		start, n = r.next, len(r.records)
+	}
+	for i := 0; i < n; i++ {
+		rec := r.records[(start+i)%len(r.records)]
+		if (!from.IsZero() && rec.Time.Before(from)) || (!to.IsZero() && rec.Time.After(to)) {
+			continue
+		}
+		l = append(l, rec)
+	}
+	return l
+}
+
+// configLogBufferSize returns controls.logger.bufferSize, DefaultLogBufferSize if not set
+func configLogBufferSize() int {
+	if !viper.IsSet("controls.logger.bufferSize") {
+		return DefaultLogBufferSize
+	}
+	return viper.GetInt("controls.logger.bufferSize")
+}
+
+// symptomDataWindow returns the time window of the symptom data parameters, given
+// as seconds since the epoch, both inclusive. 0 is not limiting.
+func symptomDataWindow(params SymptomDataParams) (from, to time.Time) {
+	if params.FromTime > 0 {
+		from = time.Unix(int64(params.FromTime), 0)
+	}
+	if params.ToTime > 0 {
+		to = time.Unix(int64(params.ToTime), int64(time.Second-1))
+	}
+	return
+}
@@ -0,0 +1,91 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogRecords(t *testing.T) {
+	l, _ := newJSONTestLogger()
+	l.SetBufferSize(3)
+	l.SetLevel(3)
+
+	l.Debug("not logged")
+	for i := 1; i <= 4; i++ {
+		l.With("n", i).Info("line %d", i)
+	}
+
+	records := l.Records(time.Time{}, time.Time{})
+	assert.Equal(t, 3, len(records))
+	assert.Equal(t, "line 2", records[0].Msg)
+	assert.Equal(t, "line 4", records[2].Msg)
+	assert.Equal(t, "INFO", records[2].Level)
+	assert.Equal(t, 4, records[2].Fields["n"])
+
+	mid := records[1].Time
+	assert.Equal(t, 2, len(l.Records(mid, time.Time{})))
+	assert.Equal(t, 2, len(l.Records(time.Time{}, mid)))
+	assert.Equal(t, 0, len(l.Records(time.Now().Add(time.Hour), time.Time{})))
+
+	l.SetBufferSize(0)
+	l.Info("dropped")
+	assert.Equal(t, 0, len(l.Records(time.Time{}, time.Time{})))
+}
+
+func TestSymptomDataLogs(t *testing.T) {
+	Logger.Info("symptom data log record")
+	now := uint64(time.Now().Unix())
+
+	var params SymptomDataParams
+	Resource.InjectRoute("/ric/v1/test/symptomparams", func(w http.ResponseWriter, r *http.Request) {
+		params = Resource.GetSymptomDataParams(w, r)
+	}, "GET")
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/ric/v1/test/symptomparams?timeout=10&fromtime=%d&totime=%d", now-60, now), nil)
+	executeRequest(req, nil)
+	assert.Equal(t, uint64(10), params.Timeout)
+	assert.Equal(t, now-60, params.FromTime)
+	assert.Equal(t, now, params.ToTime)
+
+	readLogs := func(params SymptomDataParams) []LogRecord {
+		baseDir := Resource.CollectDefaultSymptomDataWithParams("", nil, params)
+		b, err := ioutil.ReadFile(baseDir + "logs.json")
+		assert.Nil(t, err)
+		var records []LogRecord
+		assert.Nil(t, json.Unmarshal(b, &records))
+		return records
+	}
+
+	records := readLogs(params)
+	found := false
+	for _, r := range records {
+		found = found || r.Msg == "symptom data log record"
+	}
+	assert.True(t, found)
+
+	assert.Equal(t, 0, len(readLogs(SymptomDataParams{FromTime: now + 3600})))
+}
@@ -0,0 +1,112 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestIDHeader carries the request ID of a REST request. A request without it
+// gets a generated ID, which is returned in the response.
+const RequestIDHeader = "X-Request-Id"
+
+// Field names of the request-scoped loggers
+const (
+	LogFieldRequestID      = "requestId"
+	LogFieldXid            = "xid"
+	LogFieldMeid           = "meid"
+	LogFieldMtype          = "mtype"
+	LogFieldSubId          = "subId"
+	LogFieldSubscriptionID = "subscriptionId"
+)
+
+type logContextKey struct{}
+
+// NewLogContext returns a context carrying the logger
+func NewLogContext(ctx context.Context, l *Log) context.Context {
+	return context.WithValue(ctx, logContextKey{}, l)
+}
+
+// LoggerFromContext returns the logger of the context, Logger if it has none
+func LoggerFromContext(ctx context.Context) *Log {
+	if ctx != nil {
+		if l, ok := ctx.Value(logContextKey{}).(*Log); ok {
+			return l
+		}
+	}
+	return Logger
+}
+
+// WithLogFields returns a context carrying the logger of ctx with the key-value pairs added
+func WithLogFields(ctx context.Context, keyvals ...interface{}) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return NewLogContext(ctx, LoggerFromContext(ctx).With(keyvals...))
+}
+
+// RequestLogger returns the logger of a REST request injected with Router.InjectRoute,
+// it has the request ID as field
+func RequestLogger(req *http.Request) *Log {
+	return LoggerFromContext(req.Context())
+}
+
+// Context returns the context of a received message, its logger has the Xid, MEID,
+// message type and subscription ID as fields. It is set when the message is received.
+func (params *RMRParams) Context() context.Context {
+	if params.ctx == nil {
+		return NewLogContext(context.Background(), Logger.With(params.logFields()...))
+	}
+	return params.ctx
+}
+
+// Logger returns the logger of a received message, see Context
+func (params *RMRParams) Logger() *Log {
+	return LoggerFromContext(params.Context())
+}
+
+func (params *RMRParams) logFields() []interface{} {
+	fields := []interface{}{LogFieldXid, params.Xid}
+	if params.Meid != nil {
+		fields = append(fields, LogFieldMeid, params.Meid.RanName)
+	}
+	return append(fields, LogFieldMtype, params.Mtype, LogFieldSubId, params.SubId)
+}
+
+// withRequestLogger adds the request-scoped logger to the request context
+func withRequestLogger(w http.ResponseWriter, req *http.Request) *http.Request {
+	id := req.Header.Get(RequestIDHeader)
+	if id == "" {
+		id = newRequestID()
+	}
+	w.Header().Set(RequestIDHeader, id)
+
+	l := LoggerFromContext(req.Context()).With(LogFieldRequestID, id)
+	return req.WithContext(NewLogContext(req.Context(), l))
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,76 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func logFieldMap(l *Log) map[string]interface{} {
+	m := make(map[string]interface{})
+	for _, f := range l.fields {
+		m[f.key] = f.value
+	}
+	return m
+}
+
+func TestLogContext(t *testing.T) {
+	assert.Equal(t, Logger, LoggerFromContext(context.Background()))
+	assert.Equal(t, Logger, LoggerFromContext(nil))
+
+	ctx := WithLogFields(context.Background(), "a", 1)
+	ctx = WithLogFields(ctx, "b", 2)
+	assert.Equal(t, map[string]interface{}{"a": 1, "b": 2}, logFieldMap(LoggerFromContext(ctx)))
+
+	params := &RMRParams{Mtype: 12010, Xid: "xid1", SubId: 5, Meid: &RMRMeid{RanName: "gnb1"}}
+	assert.Equal(t, map[string]interface{}{
+		LogFieldXid:   "xid1",
+		LogFieldMeid:  "gnb1",
+		LogFieldMtype: 12010,
+		LogFieldSubId: 5,
+	}, logFieldMap(params.Logger()))
+
+	// A received message keeps the context set by the RMR client
+	params.ctx = WithLogFields(context.Background(), LogFieldXid, "xid1")
+	assert.Equal(t, params.ctx, params.Context())
+}
+
+func TestRequestLogger(t *testing.T) {
+	var fields map[string]interface{}
+	Resource.InjectRoute("/ric/v1/test/logcontext", func(w http.ResponseWriter, r *http.Request) {
+		fields = logFieldMap(RequestLogger(r))
+	}, "GET")
+
+	req, _ := http.NewRequest("GET", "/ric/v1/test/logcontext", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	response := executeRequest(req, nil)
+	assert.Equal(t, "req-1", response.Header().Get(RequestIDHeader))
+	assert.Equal(t, "req-1", fields[LogFieldRequestID])
+
+	req, _ = http.NewRequest("GET", "/ric/v1/test/logcontext", nil)
+	response = executeRequest(req, nil)
+	id := response.Header().Get(RequestIDHeader)
+	assert.Equal(t, 16, len(id))
+	assert.Equal(t, id, fields[LogFieldRequestID])
+}
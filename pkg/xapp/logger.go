@@ -20,68 +20,266 @@
 package xapp
 
 import (
+	"encoding/json"
 	"fmt"
 	mdclog "gerrit.o-ran-sc.org/r/com/golog"
+	"io"
+	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
+// Output formats of the logger, see SetOutputFormat
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+type logField struct {
+	key   string
+	value interface{}
+}
+
+// logOutput is shared by a logger and its children created with With and Component
+type logOutput struct {
+	mux        sync.Mutex
+	name       string
+	json       bool
+	out        io.Writer
+	mdc        map[string]string
+	components map[string]*logComponent
+	revert     *logRevert // Of the global level
+	records    *logRing
+}
+
 type Log struct {
-	logger *mdclog.MdcLogger
+	logger    *mdclog.MdcLogger
+	output    *logOutput
+	fields    []logField
+	component string
 }
 
 func NewLogger(name string) *Log {
 	l, _ := mdclog.InitLogger(name)
 	return &Log{
 		logger: l,
+		output: &logOutput{
+			name:       name,
+			out:        os.Stdout,
+			mdc:        make(map[string]string),
+			components: make(map[string]*logComponent),
+			records:    newLogRing(DefaultLogBufferSize),
+		},
 	}
 }
 
+// With returns a child logger adding the given key-value pairs to every log line
+// of the child. The fields are not visible to the parent or to other goroutines.
+// The level, format and MDC values are shared with the parent.
+func (l *Log) With(keyvals ...interface{}) *Log {
+	fields := make([]logField, len(l.fields), len(l.fields)+(len(keyvals)+1)/2)
+	copy(fields, l.fields)
+
+	for i := 0; i < len(keyvals); i += 2 {
+		if i+1 == len(keyvals) {
+			fields = append(fields, logField{"!BADKEY", keyvals[i]})
+			break
+		}
+		key, ok := keyvals[i].(string)
+		if !ok {
+			key = fmt.Sprint(keyvals[i])
+		}
+		fields = append(fields, logField{key, keyvals[i+1]})
+	}
+	return &Log{logger: l.logger, output: l.output, fields: fields, component: l.component}
+}
+
 func (l *Log) SetFormat(logMonitor int) {
 	l.logger.Mdclog_format_initialize(logMonitor)
 }
 
+// SetOutputFormat selects LogFormatText, the mdclog format with the fields of With
+// appended to the message, or LogFormatJSON, with the fields of With as typed values
+func (l *Log) SetOutputFormat(format string) error {
+	switch format {
+	case LogFormatText, "":
+		l.setJSON(false)
+	case LogFormatJSON:
+		l.setJSON(true)
+	default:
+		return fmt.Errorf("unknown log format '%s'", format)
+	}
+	return nil
+}
+
+// SetOutput sets the writer of the JSON format, os.Stdout by default
+func (l *Log) SetOutput(w io.Writer) {
+	l.output.mux.Lock()
+	defer l.output.mux.Unlock()
+	l.output.out = w
+}
+
+func (l *Log) setJSON(on bool) {
+	l.output.mux.Lock()
+	defer l.output.mux.Unlock()
+	l.output.json = on
+}
+
+// SetLevel sets the global level, a pending revert of SetLevelWithTimeout is canceled
 func (l *Log) SetLevel(level int) {
+	l.output.mux.Lock()
+	defer l.output.mux.Unlock()
 	l.logger.LevelSet(mdclog.Level(level))
+	l.output.revert = l.output.schedule(l.output.revert, 0, 0, nil)
 }
 
 func (l *Log) SetMdc(key string, value string) {
 	l.logger.MdcAdd(key, value)
+	l.output.mux.Lock()
+	l.output.mdc[key] = value
+	l.output.mux.Unlock()
 }
 
+// GetLevel returns the level of the logger, for a component logger the level of the component
 func (l *Log) GetLevel() mdclog.Level {
+	l.output.mux.Lock()
+	defer l.output.mux.Unlock()
+	return l.level()
+}
+
+// level is GetLevel with output.mux held, the mdclog level is raised only while holding it
+func (l *Log) level() mdclog.Level {
+	if l.component != "" {
+		if c, ok := l.output.components[l.component]; ok && c.level() > 0 {
+			return mdclog.Level(c.level())
+		}
+	}
 	return l.logger.LevelGet()
 }
 
 func (l *Log) Error(pattern string, args ...interface{}) {
-	if l.logger.LevelGet() < mdclog.ERR {
-		return
-	}
-	l.SetMdc("time", timeFormat())
-	l.logger.Error(pattern, args...)
+	l.log(mdclog.ERR, pattern, args...)
 }
 
 func (l *Log) Warn(pattern string, args ...interface{}) {
-	if l.logger.LevelGet() < mdclog.WARN {
-		return
-	}
-	l.SetMdc("time", timeFormat())
-	l.logger.Warning(pattern, args...)
+	l.log(mdclog.WARN, pattern, args...)
 }
 
 func (l *Log) Info(pattern string, args ...interface{}) {
-	if l.logger.LevelGet() < mdclog.INFO {
-		return
-	}
-	l.SetMdc("time", timeFormat())
-	l.logger.Info(pattern, args...)
+	l.log(mdclog.INFO, pattern, args...)
 }
 
 func (l *Log) Debug(pattern string, args ...interface{}) {
-	if l.logger.LevelGet() < mdclog.DEBUG {
+	l.log(mdclog.DEBUG, pattern, args...)
+}
+
+func (l *Log) log(level mdclog.Level, pattern string, args ...interface{}) {
+	if l.GetLevel() < level {
+		return
+	}
+
+	msg := fmt.Sprintf(pattern, args...)
+	l.output.mux.Lock()
+	isJSON := l.output.json
+	l.output.records.add(time.Now(), level, msg, l.fields)
+	l.output.mux.Unlock()
+	if isJSON {
+		l.writeJSON(level, msg)
 		return
 	}
+
+	if len(l.fields) > 0 {
+		pattern, args = "%s", []interface{}{msg + l.fieldsText()}
+	}
 	l.SetMdc("time", timeFormat())
-	l.logger.Debug(pattern, args...)
+
+	// mdclog filters by the global level, it is raised for the line of a more verbose component
+	l.output.mux.Lock()
+	defer l.output.mux.Unlock()
+	if global := l.logger.LevelGet(); global < level {
+		l.logger.LevelSet(level)
+		defer l.logger.LevelSet(global)
+	}
+	switch level {
+	case mdclog.ERR:
+		l.logger.Error(pattern, args...)
+	case mdclog.WARN:
+		l.logger.Warning(pattern, args...)
+	case mdclog.INFO:
+		l.logger.Info(pattern, args...)
+	default:
+		l.logger.Debug(pattern, args...)
+	}
+}
+
+func (l *Log) fieldsText() string {
+	var b strings.Builder
+	for _, f := range l.fields {
+		fmt.Fprintf(&b, " %s=%v", f.key, f.value)
+	}
+	return b.String()
+}
+
+type jsonLogLine struct {
+	Ts     int64                  `json:"ts"`
+	Crit   string                 `json:"crit"`
+	Id     string                 `json:"id"`
+	Mdc    map[string]string      `json:"mdc"`
+	Msg    string                 `json:"msg"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+// writeJSON writes the line in the mdclog JSON layout, with the fields of With added
+func (l *Log) writeJSON(level mdclog.Level, msg string) {
+	line := jsonLogLine{Ts: time.Now().UnixNano() / int64(time.Millisecond), Crit: logLevelName(level), Msg: msg}
+	if len(l.fields) > 0 {
+		line.Fields = make(map[string]interface{}, len(l.fields))
+		for _, f := range l.fields {
+			line.Fields[f.key] = jsonLogValue(f.value)
+		}
+	}
+
+	l.output.mux.Lock()
+	defer l.output.mux.Unlock()
+
+	line.Id = l.output.name
+	line.Mdc = make(map[string]string, len(l.output.mdc))
+	for k, v := range l.output.mdc {
+		line.Mdc[k] = v
+	}
+	b, err := json.Marshal(line)
+	if err != nil {
+		b, _ = json.Marshal(jsonLogLine{Ts: line.Ts, Crit: line.Crit, Id: line.Id, Mdc: line.Mdc, Msg: msg + l.fieldsText()})
+	}
+	l.output.out.Write(append(b, '\n'))
+}
+
+// jsonLogValue keeps the JSON types of the value, errors and other values not
+// marshaled to JSON are written as strings
+func jsonLogValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case error:
+		return t.Error()
+	case fmt.Stringer:
+		return t.String()
+	}
+	if _, err := json.Marshal(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return v
+}
+
+func logLevelName(level mdclog.Level) string {
+	switch level {
+	case mdclog.ERR:
+		return "ERROR"
+	case mdclog.WARN:
+		return "WARNING"
+	case mdclog.INFO:
+		return "INFO"
+	}
+	return "DEBUG"
 }
 
 func timeFormat() string {
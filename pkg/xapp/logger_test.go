@@ -0,0 +1,116 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	mdclog "gerrit.o-ran-sc.org/r/com/golog"
+)
+
+func newJSONTestLogger() (*Log, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	l := NewLogger("test")
+	l.SetLevel(4)
+	l.SetOutput(buf)
+	l.SetOutputFormat(LogFormatJSON)
+	return l, buf
+}
+
+func decodeLogLines(t *testing.T, buf *bytes.Buffer) []jsonLogLine {
+	var lines []jsonLogLine
+	for _, s := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var line jsonLogLine
+		assert.Nil(t, json.Unmarshal([]byte(s), &line), s)
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestLoggerWith(t *testing.T) {
+	l, buf := newJSONTestLogger()
+	l.SetMdc("service", "xapp")
+
+	child := l.With("subId", 7, "ok", true, "err", errors.New("failed"))
+	grandchild := child.With("node", "gnb1", "odd")
+
+	child.Info("subscribed %s", "now")
+	l.Warn("parent")
+	grandchild.Error("failed")
+	l.Debug("debug")
+
+	lines := decodeLogLines(t, buf)
+	assert.Equal(t, 4, len(lines))
+
+	assert.Equal(t, "INFO", lines[0].Crit)
+	assert.Equal(t, "subscribed now", lines[0].Msg)
+	assert.Equal(t, "test", lines[0].Id)
+	assert.Equal(t, "xapp", lines[0].Mdc["service"])
+	assert.Equal(t, map[string]interface{}{"subId": float64(7), "ok": true, "err": "failed"}, lines[0].Fields)
+
+	assert.Equal(t, "WARNING", lines[1].Crit)
+	assert.Nil(t, lines[1].Fields)
+
+	assert.Equal(t, "gnb1", lines[2].Fields["node"])
+	assert.Equal(t, "odd", lines[2].Fields["!BADKEY"])
+	assert.Equal(t, float64(7), lines[2].Fields["subId"])
+
+	assert.Equal(t, "DEBUG", lines[3].Crit)
+}
+
+func TestLoggerLevelAndFormat(t *testing.T) {
+	l, buf := newJSONTestLogger()
+	l.SetLevel(2)
+	l.With("a", 1).Info("skipped")
+	assert.Equal(t, 0, buf.Len())
+
+	assert.NotNil(t, l.SetOutputFormat("xml"))
+	assert.Nil(t, l.SetOutputFormat(LogFormatText))
+	l.SetLevel(4)
+	l.With("a", 1).Info("text format")
+	assert.Equal(t, 0, buf.Len())
+	assert.Equal(t, " a=1 b=x", l.With("a", 1, "b", "x").fieldsText())
+}
+
+func TestLoggerTextComponentLevel(t *testing.T) {
+	l, buf := newJSONTestLogger()
+	l.SetOutputFormat(LogFormatText)
+	l.SetLevel(2)
+	rmr := l.Component("rmr")
+	l.SetComponentLevels(map[string]int{"rmr": 4})
+
+	// Written through mdclog, its level is raised for the line only
+	rmr.Debug("rmr debug")
+	l.Debug("global debug")
+	assert.Equal(t, 0, buf.Len())
+	assert.Equal(t, mdclog.Level(2), l.logger.LevelGet())
+	assert.Equal(t, mdclog.Level(4), rmr.GetLevel())
+
+	records := l.Records(time.Time{}, time.Time{})
+	assert.Equal(t, 1, len(records))
+	assert.Equal(t, "rmr debug", records[0].Msg)
+}
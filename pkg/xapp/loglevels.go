@@ -0,0 +1,252 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	mdclog "gerrit.o-ran-sc.org/r/com/golog"
+	"github.com/spf13/viper"
+)
+
+// LogLevels is the body of GET LoggerURL
+type LogLevels struct {
+	Level      int                 `json:"level"`
+	RevertAt   *time.Time          `json:"revertAt,omitempty"`
+	Components []ComponentLogLevel `json:"components"`
+}
+
+// ComponentLogLevel is the level of a component logger. Level is the effective level,
+// the runtime override, the configured level (controls.logger.components) or the global one.
+type ComponentLogLevel struct {
+	Name       string     `json:"name"`
+	Level      int        `json:"level"`
+	Configured int        `json:"configured,omitempty"`
+	Override   int        `json:"override,omitempty"`
+	RevertAt   *time.Time `json:"revertAt,omitempty"`
+}
+
+// LogLevelUpdate is the body of PUT LoggerURL. Without a component the global level
+// is set. Level 0 removes the override of a component. With a timeout in seconds
+// the previous level is restored after the timeout.
+type LogLevelUpdate struct {
+	Component string `json:"component,omitempty"`
+	Level     int    `json:"level"`
+	Timeout   int    `json:"timeout,omitempty"`
+}
+
+type logRevert struct {
+	timer    *time.Timer
+	at       time.Time
+	level    int // Restored level
+	canceled bool
+}
+
+type logComponent struct {
+	configured int
+	override   int
+	revert     *logRevert
+}
+
+// Component returns a logger of a named part of the xApp, e.g. "rmr". The level of
+// the component is set under controls.logger.components or with SetComponentLevel,
+// otherwise the global level is used. The lines have the field component=<name>.
+func (l *Log) Component(name string) *Log {
+	l.output.mux.Lock()
+	if _, ok := l.output.components[name]; !ok {
+		l.output.components[name] = &logComponent{}
+	}
+	l.output.mux.Unlock()
+
+	c := l.With("component", name)
+	c.component = name
+	return c
+}
+
+// SetComponentLevels sets the configured levels of the components, the others are reset
+func (l *Log) SetComponentLevels(levels map[string]int) {
+	l.output.mux.Lock()
+	defer l.output.mux.Unlock()
+
+	for _, c := range l.output.components {
+		c.configured = 0
+	}
+	for name, level := range levels {
+		c, ok := l.output.components[name]
+		if !ok {
+			c = &logComponent{}
+			l.output.components[name] = c
+		}
+		c.configured = level
+	}
+}
+
+// SetComponentLevel overrides the level of a component at runtime, level 0 removes the
+// override. With a timeout the previous override is restored after the timeout.
+func (l *Log) SetComponentLevel(name string, level int, timeout time.Duration) error {
+	if level < 0 || level > int(mdclog.DEBUG) {
+		return fmt.Errorf("invalid log level %d", level)
+	}
+
+	l.output.mux.Lock()
+	defer l.output.mux.Unlock()
+
+	c, ok := l.output.components[name]
+	if !ok {
+		c = &logComponent{}
+		l.output.components[name] = c
+	}
+	prev := c.override
+	if c.revert != nil {
+		prev = c.revert.level
+	}
+	c.override = level
+	c.revert = l.output.schedule(c.revert, timeout, prev, func() {
+		c.override = prev
+		c.revert = nil
+	})
+	return nil
+}
+
+// SetLevelWithTimeout sets the global level, the previous level is restored after the timeout
+func (l *Log) SetLevelWithTimeout(level int, timeout time.Duration) error {
+	if level < int(mdclog.ERR) || level > int(mdclog.DEBUG) {
+		return fmt.Errorf("invalid log level %d", level)
+	}
+
+	l.output.mux.Lock()
+	defer l.output.mux.Unlock()
+
+	prev := int(l.logger.LevelGet())
+	if l.output.revert != nil {
+		prev = l.output.revert.level
+	}
+	l.logger.LevelSet(mdclog.Level(level))
+	l.output.revert = l.output.schedule(l.output.revert, timeout, prev, func() {
+		l.logger.LevelSet(mdclog.Level(prev))
+		l.output.revert = nil
+	})
+	return nil
+}
+
+// LogLevels returns the global level and the levels of the components
+func (l *Log) LogLevels() LogLevels {
+	global := int(l.logger.LevelGet())
+
+	l.output.mux.Lock()
+	defer l.output.mux.Unlock()
+
+	res := LogLevels{Level: global, Components: []ComponentLogLevel{}}
+	if r := l.output.revert; r != nil {
+		res.RevertAt = &r.at
+	}
+	for name, c := range l.output.components {
+		cl := ComponentLogLevel{Name: name, Level: global, Configured: c.configured, Override: c.override}
+		if level := c.level(); level > 0 {
+			cl.Level = level
+		}
+		if c.revert != nil {
+			at := c.revert.at
+			cl.RevertAt = &at
+		}
+		res.Components = append(res.Components, cl)
+	}
+	sort.Slice(res.Components, func(i, j int) bool { return res.Components[i].Name < res.Components[j].Name })
+	return res
+}
+
+// schedule replaces the pending revert with f restoring level after timeout, o.mux must
+// be held. A new level without a timeout cancels the pending revert.
+func (o *logOutput) schedule(prev *logRevert, timeout time.Duration, level int, f func()) *logRevert {
+	if prev != nil {
+		prev.timer.Stop()
+		prev.canceled = true
+	}
+	if timeout <= 0 {
+		return nil
+	}
+
+	r := &logRevert{at: time.Now().Add(timeout), level: level}
+	r.timer = time.AfterFunc(timeout, func() {
+		o.mux.Lock()
+		defer o.mux.Unlock()
+		if !r.canceled {
+			f()
+		}
+	})
+	return r
+}
+
+// level returns the override or the configured level, 0 if neither is set
+func (c *logComponent) level() int {
+	if c.override > 0 {
+		return c.override
+	}
+	return c.configured
+}
+
+// configComponentLevels returns the levels under controls.logger.components
+func configComponentLevels() map[string]int {
+	levels := make(map[string]int)
+	for name := range viper.GetStringMap("controls.logger.components") {
+		levels[name] = viper.GetInt("controls.logger.components." + name)
+	}
+	return levels
+}
+
+func loggerGetHandler(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, Logger.LogLevels())
+}
+
+func loggerPutHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		respondWithError(w, http.StatusBadRequest, "no body")
+		return
+	}
+	defer r.Body.Close()
+
+	var u LogLevelUpdate
+	if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+		return
+	}
+	if u.Timeout < 0 {
+		respondWithError(w, http.StatusBadRequest, "invalid timeout")
+		return
+	}
+
+	var err error
+	timeout := time.Duration(u.Timeout) * time.Second
+	if u.Component == "" {
+		err = Logger.SetLevelWithTimeout(u.Level, timeout)
+	} else {
+		err = Logger.SetComponentLevel(u.Component, u.Level, timeout)
+	}
+	Logger.Info("Logger audit: PUT component=%s level=%d timeout=%d remote=%s result=%v", u.Component, u.Level, u.Timeout, r.RemoteAddr, errorOrOk(err))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, Logger.LogLevels())
+}
@@ -0,0 +1,120 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	mdclog "gerrit.o-ran-sc.org/r/com/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+func componentLevel(l LogLevels, name string) *ComponentLogLevel {
+	for _, c := range l.Components {
+		if c.Name == name {
+			return &c
+		}
+	}
+	return nil
+}
+
+func TestLoggerComponents(t *testing.T) {
+	l, buf := newJSONTestLogger()
+	l.SetLevel(2)
+	rmr := l.Component("rmr")
+	sdl := l.Component("sdl")
+
+	l.SetComponentLevels(map[string]int{"rmr": 4})
+	assert.Equal(t, mdclog.DEBUG, rmr.GetLevel())
+	assert.Equal(t, mdclog.WARN, sdl.GetLevel())
+
+	rmr.Debug("rmr debug")
+	sdl.Info("sdl info")
+	l.Info("global info")
+	lines := decodeLogLines(t, buf)
+	assert.Equal(t, 1, len(lines))
+	assert.Equal(t, "rmr", lines[0].Fields["component"])
+
+	// Override with revert
+	assert.Nil(t, l.SetComponentLevel("sdl", 3, 50*time.Millisecond))
+	assert.Nil(t, l.SetComponentLevel("sdl", 4, 50*time.Millisecond))
+	assert.Equal(t, mdclog.DEBUG, sdl.GetLevel())
+	levels := l.LogLevels()
+	assert.Equal(t, 2, levels.Level)
+	assert.Equal(t, 4, componentLevel(levels, "sdl").Override)
+	assert.NotNil(t, componentLevel(levels, "sdl").RevertAt)
+	assert.Equal(t, 4, componentLevel(levels, "rmr").Configured)
+
+	assert.Eventually(t, func() bool { return sdl.GetLevel() == mdclog.WARN }, time.Second, 10*time.Millisecond)
+	assert.Nil(t, componentLevel(l.LogLevels(), "sdl").RevertAt)
+	assert.Equal(t, 0, componentLevel(l.LogLevels(), "sdl").Override)
+
+	assert.NotNil(t, l.SetComponentLevel("sdl", 5, 0))
+	assert.NotNil(t, l.SetLevelWithTimeout(0, 0))
+
+	// Global level with revert
+	assert.Nil(t, l.SetLevelWithTimeout(4, 50*time.Millisecond))
+	assert.Equal(t, mdclog.DEBUG, sdl.GetLevel())
+	assert.NotNil(t, l.LogLevels().RevertAt)
+	assert.Eventually(t, func() bool { return l.GetLevel() == mdclog.WARN }, time.Second, 10*time.Millisecond)
+
+	// SetLevel cancels the pending revert
+	assert.Nil(t, l.SetLevelWithTimeout(4, 50*time.Millisecond))
+	l.SetLevel(3)
+	assert.Nil(t, l.LogLevels().RevertAt)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, mdclog.INFO, l.GetLevel())
+
+	l.SetComponentLevels(nil)
+	assert.Equal(t, mdclog.INFO, rmr.GetLevel())
+}
+
+func TestLoggerHandlers(t *testing.T) {
+	level := int(Logger.GetLevel())
+	defer Logger.SetLevel(level)
+	Logger.Component("testcomp")
+
+	put := func(body string) int {
+		req, _ := http.NewRequest("PUT", LoggerURL, bytes.NewBufferString(body))
+		return executeRequest(req, nil).Code
+	}
+	assert.Equal(t, http.StatusOK, put(`{"component": "testcomp", "level": 4, "timeout": 60}`))
+	assert.Equal(t, http.StatusBadRequest, put(`{"component": "testcomp", "level": 9}`))
+	assert.Equal(t, http.StatusBadRequest, put(`{"level": 4, "timeout": -1}`))
+	assert.Equal(t, http.StatusBadRequest, put(`{"level": `))
+
+	req, _ := http.NewRequest("GET", LoggerURL, nil)
+	response := executeRequest(req, nil)
+	checkResponseCode(t, http.StatusOK, response.Code)
+	var levels LogLevels
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&levels))
+	assert.Equal(t, level, levels.Level)
+	c := componentLevel(levels, "testcomp")
+	assert.NotNil(t, c)
+	assert.Equal(t, 4, c.Level)
+	assert.NotNil(t, c.RevertAt)
+
+	assert.Equal(t, http.StatusOK, put(`{"component": "testcomp", "level": 0}`))
+	assert.Equal(t, level, componentLevel(Logger.LogLevels(), "testcomp").Level)
+}
@@ -0,0 +1,110 @@
+//go:build go1.21
+
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"context"
+	"log/slog"
+
+	mdclog "gerrit.o-ran-sc.org/r/com/golog"
+)
+
+// slogHandler is a slog.Handler writing to a Log, the attributes become fields of With
+type slogHandler struct {
+	log    *Log
+	prefix string // Group names, each followed by a dot
+}
+
+// Slog returns a slog.Logger writing to the logger, e.g. for libraries using log/slog.
+// Available when built with Go 1.21 or later.
+func (l *Log) Slog() *slog.Logger {
+	return slog.New(NewSlogHandler(l))
+}
+
+// NewSlogHandler returns a slog.Handler writing to the logger with its level and format
+func NewSlogHandler(l *Log) slog.Handler {
+	return &slogHandler{log: l}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.log.GetLevel() >= slogToLevel(level)
+}
+
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	var keyvals []interface{}
+	r.Attrs(func(a slog.Attr) bool {
+		keyvals = appendSlogAttr(keyvals, h.prefix, a)
+		return true
+	})
+
+	l := h.log
+	if len(keyvals) > 0 {
+		l = l.With(keyvals...)
+	}
+	l.log(slogToLevel(r.Level), "%s", r.Message)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var keyvals []interface{}
+	for _, a := range attrs {
+		keyvals = appendSlogAttr(keyvals, h.prefix, a)
+	}
+	return &slogHandler{log: h.log.With(keyvals...), prefix: h.prefix}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{log: h.log, prefix: h.prefix + name + "."}
+}
+
+// appendSlogAttr appends the attribute as key-value pairs, groups are flattened to dotted keys
+func appendSlogAttr(keyvals []interface{}, prefix string, a slog.Attr) []interface{} {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			keyvals = appendSlogAttr(keyvals, prefix, ga)
+		}
+		return keyvals
+	}
+	if a.Key == "" {
+		return keyvals
+	}
+	return append(keyvals, prefix+a.Key, v.Any())
+}
+
+func slogToLevel(level slog.Level) mdclog.Level {
+	switch {
+	case level >= slog.LevelError:
+		return mdclog.ERR
+	case level >= slog.LevelWarn:
+		return mdclog.WARN
+	case level >= slog.LevelInfo:
+		return mdclog.INFO
+	}
+	return mdclog.DEBUG
+}
@@ -0,0 +1,53 @@
+//go:build go1.21
+
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerSlog(t *testing.T) {
+	l, buf := newJSONTestLogger()
+	l.SetLevel(3)
+
+	s := l.With("app", "test").Slog().With("conn", 1).WithGroup("req")
+	assert.False(t, s.Enabled(context.Background(), slog.LevelDebug))
+	assert.True(t, s.Enabled(context.Background(), slog.LevelWarn))
+
+	s.Debug("skipped")
+	s.Warn("slow request", "ms", 250, slog.Group("peer", "host", "gnb1"))
+
+	lines := decodeLogLines(t, buf)
+	assert.Equal(t, 1, len(lines))
+	assert.Equal(t, "WARNING", lines[0].Crit)
+	assert.Equal(t, "slow request", lines[0].Msg)
+	assert.Equal(t, map[string]interface{}{
+		"app":           "test",
+		"conn":          float64(1),
+		"req.ms":        float64(250),
+		"req.peer.host": "gnb1",
+	}, lines[0].Fields)
+}
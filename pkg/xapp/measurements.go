@@ -0,0 +1,241 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/descriptor"
+)
+
+// Types of the metrics declared in the descriptor "measurements" section
+const (
+	MeasurementTypeCounter   = "counter"
+	MeasurementTypeGauge     = "gauge"
+	MeasurementTypeHistogram = "histogram"
+)
+
+// Measurement is an entry of the descriptor "measurements" section
+type Measurement struct {
+	MoId         string              `json:"moId"`
+	MeasType     string              `json:"measType"`
+	MeasId       string              `json:"measId"`
+	MeasInterval string              `json:"measInterval"`
+	Metrics      []MeasurementMetric `json:"metrics"`
+}
+
+// MeasurementMetric is a metric declared in the descriptor. Name is the exported name,
+// e.g. "ricxapp_ExampleXapp_SgNBAdditionRequest", a name without the namespace of the
+// metrics gets it as prefix. A metric with labels is registered as a vector.
+type MeasurementMetric struct {
+	ObjectName     string    `json:"objectName"`
+	ObjectInstance string    `json:"objectInstance"`
+	Name           string    `json:"name"`
+	Type           string    `json:"type"`
+	Description    string    `json:"description"`
+	Unit           string    `json:"unit,omitempty"`
+	Labels         []string  `json:"labels,omitempty"`
+	Buckets        []float64 `json:"buckets,omitempty"` // Of a histogram, prometheus.DefBuckets if not given
+}
+
+// declaredMetric is a registered MeasurementMetric, value is a Counter, Gauge,
+// Histogram or a vector of them
+type declaredMetric struct {
+	typ   string
+	value interface{}
+}
+
+// configMeasurements returns the descriptor "measurements" section
+func configMeasurements() []Measurement {
+	var ms []Measurement
+	if err := viper.UnmarshalKey("measurements", &ms); err != nil {
+		Logger.Error("Invalid measurements: %v", err)
+		return nil
+	}
+	return ms
+}
+
+// RegisterMeasurements registers the metrics declared in the measurements, the invalid
+// ones and the ones conflicting with a metric of another type are skipped. A measurement
+// with the same moId and measId replaces the earlier one. The metrics are retrieved by
+// name with DeclaredCounter and friends, the measurements list them by exported name.
+func (m *Metrics) RegisterMeasurements(ms []Measurement) {
+	reg := m.getReg()
+	for _, meas := range ms {
+		registered := meas
+		registered.Metrics = []MeasurementMetric{}
+		for _, mm := range meas.Metrics {
+			if v := m.registerMeasurementMetric(mm); v != nil {
+				mm.Name = m.exportedMetricName(mm.Name)
+				reg.measurementsLock.Lock()
+				reg.declared[mm.Name] = declaredMetric{mm.Type, v}
+				reg.measurementsLock.Unlock()
+				registered.Metrics = append(registered.Metrics, mm)
+			}
+		}
+		reg.measurementsLock.Lock()
+		replaced := false
+		for i, prev := range reg.measurements {
+			if prev.MoId == meas.MoId && prev.MeasId == meas.MeasId {
+				reg.measurements[i], replaced = registered, true
+			}
+		}
+		if !replaced {
+			reg.measurements = append(reg.measurements, registered)
+		}
+		reg.measurementsLock.Unlock()
+	}
+}
+
+func (m *Metrics) registerMeasurementMetric(mm MeasurementMetric) interface{} {
+	reg := m.getReg()
+	if !descriptor.MetricNameRegexp.MatchString(mm.Name) {
+		Logger.Warn("Measurement metric %q: invalid name", mm.Name)
+		return nil
+	}
+	switch mm.Type {
+	case MeasurementTypeCounter, MeasurementTypeGauge, MeasurementTypeHistogram:
+	default:
+		Logger.Warn("Measurement metric %q: unknown type %q", mm.Name, mm.Type)
+		return nil
+	}
+
+	namespace, subsystem, name := m.splitMetricName(m.exportedMetricName(mm.Name))
+	kind := mm.Type
+	if len(mm.Labels) > 0 {
+		kind += " vector"
+	}
+	if cached := reg.cachedKind(m.getFullName(observerOpts(namespace, subsystem, name), []string{})); cached != "" && cached != kind {
+		Logger.Warn("Measurement metric %q: conflicts an existing %s", mm.Name, cached)
+		return nil
+	}
+	sm := &Metrics{Namespace: namespace, reg: reg}
+	help := mm.Description
+	if mm.Unit != "" {
+		help += " (" + mm.Unit + ")"
+	}
+
+	switch mm.Type {
+	case MeasurementTypeCounter:
+		opts := CounterOpts{Name: name, Help: help}
+		if len(mm.Labels) == 0 {
+			if c := sm.RegisterCounter(opts, subsystem); c != nil {
+				return c
+			}
+		} else if v := sm.RegisterCounterVec(opts, mm.Labels, subsystem, VecOpts{}); v != nil {
+			return v
+		}
+	case MeasurementTypeGauge:
+		opts := CounterOpts{Name: name, Help: help}
+		if len(mm.Labels) == 0 {
+			if g := sm.RegisterGauge(opts, subsystem); g != nil {
+				return g
+			}
+		} else if v := sm.RegisterGaugeVec(opts, mm.Labels, subsystem, VecOpts{}); v != nil {
+			return v
+		}
+	case MeasurementTypeHistogram:
+		opts := HistogramOpts{Name: name, Help: help, Buckets: mm.Buckets}
+		if len(mm.Labels) == 0 {
+			if h := sm.RegisterHistogram(opts, subsystem); h != nil {
+				return h
+			}
+		} else if v := sm.RegisterHistogramVec(opts, mm.Labels, subsystem, VecOpts{}); v != nil {
+			return v
+		}
+	}
+	Logger.Warn("Measurement metric %q: conflicts an existing metric", mm.Name)
+	return nil
+}
+
+// exportedMetricName prefixes a declared name with the namespace of the metrics if it
+// does not have it, e.g. "ExampleXapp_SgNBAdditionRequest"
+func (m *Metrics) exportedMetricName(name string) string {
+	if m.Namespace == "" || strings.HasPrefix(name, m.Namespace+"_") {
+		return name
+	}
+	return m.Namespace + "_" + name
+}
+
+// splitMetricName splits a full name in the namespace of the metrics into namespace,
+// subsystem and name, so that the cached metrics are shared with RegisterCounter and
+// friends. Other names are used as such.
+func (m *Metrics) splitMetricName(full string) (namespace, subsystem, name string) {
+	if m.Namespace == "" || !strings.HasPrefix(full, m.Namespace+"_") {
+		return "", "", full
+	}
+	rest := strings.TrimPrefix(full, m.Namespace+"_")
+	if i := strings.Index(rest, "_"); i > 0 && i < len(rest)-1 {
+		return m.Namespace, rest[:i], rest[i+1:]
+	}
+	return m.Namespace, "", rest
+}
+
+// Measurements returns the measurements registered, with the metrics that were registered
+func (m *Metrics) Measurements() []Measurement {
+	reg := m.getReg()
+	reg.measurementsLock.Lock()
+	defer reg.measurementsLock.Unlock()
+	return append([]Measurement{}, reg.measurements...)
+}
+
+func (m *Metrics) getDeclaredMetric(name, typ string) interface{} {
+	reg := m.getReg()
+	reg.measurementsLock.Lock()
+	defer reg.measurementsLock.Unlock()
+	if d, ok := reg.declared[m.exportedMetricName(name)]; ok && d.typ == typ {
+		return d.value
+	}
+	return nil
+}
+
+// DeclaredCounter returns the counter declared in the measurements, nil if there is none
+func (m *Metrics) DeclaredCounter(name string) Counter {
+	c, _ := m.getDeclaredMetric(name, MeasurementTypeCounter).(Counter)
+	return c
+}
+
+// DeclaredCounterVec returns the counter vector declared with labels, nil if there is none
+func (m *Metrics) DeclaredCounterVec(name string) *CounterVec {
+	v, _ := m.getDeclaredMetric(name, MeasurementTypeCounter).(*CounterVec)
+	return v
+}
+
+func (m *Metrics) DeclaredGauge(name string) Gauge {
+	g, _ := m.getDeclaredMetric(name, MeasurementTypeGauge).(Gauge)
+	return g
+}
+
+func (m *Metrics) DeclaredGaugeVec(name string) *GaugeVec {
+	v, _ := m.getDeclaredMetric(name, MeasurementTypeGauge).(*GaugeVec)
+	return v
+}
+
+func (m *Metrics) DeclaredHistogram(name string) Histogram {
+	h, _ := m.getDeclaredMetric(name, MeasurementTypeHistogram).(Histogram)
+	return h
+}
+
+func (m *Metrics) DeclaredHistogramVec(name string) *HistogramVec {
+	v, _ := m.getDeclaredMetric(name, MeasurementTypeHistogram).(*HistogramVec)
+	return v
+}
@@ -0,0 +1,99 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasurements(t *testing.T) {
+	viper.Set("measurements", []interface{}{
+		map[string]interface{}{
+			"moId": "XAPP-test", "measType": "Streaming", "measId": "91001", "measInterval": 60,
+			"metrics": []interface{}{
+				map[string]interface{}{"name": "ricxapp_TestMeasurements_Requests", "type": "counter", "description": "Requests"},
+				map[string]interface{}{"name": "ricxapp_TestMeasurements_CellLoad", "type": "gauge", "description": "Load", "unit": "percent", "labels": []string{"cell"}},
+				map[string]interface{}{"name": "TestMeasurements_Latency", "type": "histogram", "description": "Latency", "buckets": []float64{0.1, 1}},
+				map[string]interface{}{"name": "ricxapp_TestMeasurements_Other", "type": "summary"},
+			},
+		},
+	})
+	defer viper.Set("measurements", []interface{}{})
+
+	ms := configMeasurements()
+	assert.Equal(t, 1, len(ms))
+	assert.Equal(t, "60", ms[0].MeasInterval)
+	assert.Equal(t, []string{"cell"}, ms[0].Metrics[1].Labels)
+	Metric.RegisterMeasurements(ms)
+
+	// Shared with the metrics registered in code
+	c := Metric.DeclaredCounter("ricxapp_TestMeasurements_Requests")
+	assert.NotNil(t, c)
+	assert.Equal(t, c, Metric.RegisterCounter(CounterOpts{Name: "Requests", Help: "Requests"}, "TestMeasurements"))
+	c.Inc()
+	assert.Nil(t, Metric.DeclaredGauge("ricxapp_TestMeasurements_Requests"))
+
+	v := Metric.DeclaredGaugeVec("ricxapp_TestMeasurements_CellLoad")
+	assert.NotNil(t, v)
+	v.WithLabelValues("c1").Set(42)
+	Metric.DeclaredHistogram("TestMeasurements_Latency").Observe(0.5)
+	assert.Nil(t, Metric.DeclaredCounter("ricxapp_TestMeasurements_Other"))
+
+	metrics, err := Resource.GetLocalMetrics()
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(metrics, "ricxapp_TestMeasurements_Requests 1"))
+	assert.True(t, strings.Contains(metrics, "# HELP ricxapp_TestMeasurements_CellLoad Load (percent)"))
+	assert.True(t, strings.Contains(metrics, `ricxapp_TestMeasurements_CellLoad{cell="c1"} 42`))
+	assert.True(t, strings.Contains(metrics, `ricxapp_TestMeasurements_Latency_bucket{le="1"} 1`))
+	assert.Equal(t, Metric.DeclaredHistogram("TestMeasurements_Latency"), Metric.DeclaredHistogram("ricxapp_TestMeasurements_Latency"))
+
+	// The inventory has the registered metrics only, registering again replaces it
+	Metric.RegisterMeasurements(ms)
+	var inv []Measurement
+	for _, m := range Metric.Measurements() {
+		if m.MoId == "XAPP-test" {
+			inv = append(inv, m)
+		}
+	}
+	assert.Equal(t, 1, len(inv))
+	assert.Equal(t, 3, len(inv[0].Metrics))
+	assert.Equal(t, "ricxapp_TestMeasurements_Latency", inv[0].Metrics[2].Name)
+}
+
+func TestMeasurementsTypeConflict(t *testing.T) {
+	c := Metric.RegisterCounter(CounterOpts{Name: "Conflict", Help: "Conflict"}, "TestMeasurementsTypeConflict")
+	assert.NotNil(t, c)
+
+	Metric.RegisterMeasurements([]Measurement{{
+		MoId: "XAPP-conflict", MeasId: "91002",
+		Metrics: []MeasurementMetric{
+			{Name: "ricxapp_TestMeasurementsTypeConflict_Conflict", Type: MeasurementTypeGauge, Description: "Conflict"},
+			{Name: "TestMeasurementsTypeConflict_Conflict", Type: MeasurementTypeCounter, Labels: []string{"cell"}, Description: "Conflict"},
+			{Name: "TestMeasurementsTypeConflict_Conflict", Type: MeasurementTypeCounter, Description: "Conflict"},
+		},
+	}})
+	assert.Nil(t, Metric.DeclaredGauge("ricxapp_TestMeasurementsTypeConflict_Conflict"))
+	assert.Nil(t, Metric.DeclaredCounterVec("ricxapp_TestMeasurementsTypeConflict_Conflict"))
+	assert.Equal(t, c, Metric.DeclaredCounter("ricxapp_TestMeasurementsTypeConflict_Conflict"))
+}
@@ -25,7 +25,6 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
@@ -55,12 +54,14 @@ type CounterVec struct {
 	Vec    *prometheus.CounterVec
 	Opts   CounterOpts
 	Labels []string
+	series *metricSeries
 }
 
 type GaugeVec struct {
 	Vec    *prometheus.GaugeVec
 	Opts   CounterOpts
 	Labels []string
+	series *metricSeries
 }
 
 //-----------------------------------------------------------------------------
@@ -97,8 +98,12 @@ type MetricGroupsCache struct {
 	sync.RWMutex //This is for map locking
 	counters     map[string]Counter
 	gauges       map[string]Gauge
+	histograms   map[string]Histogram
+	summaries    map[string]Summary
 	regcnt       MetricGroupsCacheCounterRegisterer
 	reggau       MetricGroupsCacheGaugeRegisterer
+	reghis       MetricGroupsCacheHistogramRegisterer
+	regsum       MetricGroupsCacheSummaryRegisterer
 }
 
 func (met *MetricGroupsCache) Registerer(regcnt MetricGroupsCacheCounterRegisterer, reggau MetricGroupsCacheGaugeRegisterer) {
@@ -261,6 +266,8 @@ func NewMetricGroupsCache() *MetricGroupsCache {
 	entry := &MetricGroupsCache{}
 	entry.counters = make(map[string]Counter)
 	entry.gauges = make(map[string]Gauge)
+	entry.histograms = make(map[string]Histogram)
+	entry.summaries = make(map[string]Summary)
 	entry.regcnt = nil
 	entry.reggau = nil
 	return entry
@@ -273,28 +280,12 @@ func NewMetricGroupsCacheWithRegisterers(regcnt MetricGroupsCacheCounterRegister
 	return entry
 }
 
-//-----------------------------------------------------------------------------
-// All counters/gauges registered via Metrics instances:
-// Counter names are build from: namespace, subsystem, metric and possible labels
-//-----------------------------------------------------------------------------
-var globalLock sync.Mutex
-var cache_allcounters map[string]Counter
-var cache_allgauges map[string]Gauge
-var cache_allcountervects map[string]CounterVec
-var cache_allgaugevects map[string]GaugeVec
-
-func init() {
-	cache_allcounters = make(map[string]Counter)
-	cache_allgauges = make(map[string]Gauge)
-	cache_allcountervects = make(map[string]CounterVec)
-	cache_allgaugevects = make(map[string]GaugeVec)
-}
-
 //-----------------------------------------------------------------------------
 //
 //-----------------------------------------------------------------------------
 type Metrics struct {
 	Namespace string
+	reg       *metricsRegistry
 }
 
 func NewMetrics(url, namespace string, r *mux.Router) *Metrics {
@@ -303,10 +294,12 @@ func NewMetrics(url, namespace string, r *mux.Router) *Metrics {
 	}
 	Logger.Info("Serving metrics on: url=%s namespace=%s", url, namespace)
 
+	m := &Metrics{Namespace: namespace, reg: newMetricsRegistry()}
+
 	// Expose 'metrics' endpoint with standard golang metrics used by prometheus
-	r.Handle(url, promhttp.Handler())
+	r.Handle(url, promhttp.HandlerFor(m.Gatherer(), promhttp.HandlerOpts{}))
 
-	return &Metrics{Namespace: namespace}
+	return m
 }
 
 /*
@@ -328,20 +321,25 @@ func (m *Metrics) getFullName(opts prometheus.Opts, labels []string) string {
 //
 //
 func (m *Metrics) RegisterCounter(opts CounterOpts, subsytem string) Counter {
-	globalLock.Lock()
-	defer globalLock.Unlock()
+	reg := m.getReg()
+	reg.Lock()
+	defer reg.Unlock()
 	opts.Namespace = m.Namespace
 	opts.Subsystem = subsytem
 	id := m.getFullName(prometheus.Opts(opts), []string{})
-	if _, ok := cache_allcountervects[id]; ok {
+	if _, ok := reg.countervects[id]; ok {
 		Logger.Warn("Register new counter with opts: %v, name conflicts existing counter vector", opts)
 		return nil
 	}
-	if _, ok := cache_allcounters[id]; !ok {
+	if _, ok := reg.counters[id]; !ok {
 		Logger.Debug("Register new counter with opts: %v", opts)
-		cache_allcounters[id] = promauto.NewCounter(prometheus.CounterOpts(opts))
+		c := prometheus.NewCounter(prometheus.CounterOpts(opts))
+		if !reg.register(prometheus.Opts(opts), id, c) {
+			return nil
+		}
+		reg.counters[id] = c
 	}
-	return cache_allcounters[id]
+	return reg.counters[id]
 }
 
 //
@@ -359,34 +357,39 @@ func (m *Metrics) RegisterCounterGroup(optsgroup []CounterOpts, subsytem string)
 //
 //
 func (m *Metrics) RegisterLabeledCounter(opts CounterOpts, labelNames []string, labelValues []string, subsytem string) Counter {
-	globalLock.Lock()
-	defer globalLock.Unlock()
+	reg := m.getReg()
+	reg.Lock()
+	defer reg.Unlock()
 	opts.Namespace = m.Namespace
 	opts.Subsystem = subsytem
 	vecid := m.getFullName(prometheus.Opts(opts), []string{})
-	if _, ok := cache_allcounters[vecid]; ok {
+	if _, ok := reg.counters[vecid]; ok {
 		Logger.Warn("Register new counter vector with opts: %v labelNames: %v, name conflicts existing counter", opts, labelNames)
 		return nil
 	}
-	if _, ok := cache_allcountervects[vecid]; !ok {
+	if _, ok := reg.countervects[vecid]; !ok {
 		Logger.Debug("Register new counter vector with opts: %v labelNames: %v", opts, labelNames)
 		entry := CounterVec{}
 		entry.Opts = opts
 		entry.Labels = labelNames
-		entry.Vec = promauto.NewCounterVec(prometheus.CounterOpts(entry.Opts), entry.Labels)
-		cache_allcountervects[vecid] = entry
+		entry.Vec = prometheus.NewCounterVec(prometheus.CounterOpts(entry.Opts), entry.Labels)
+		if !reg.register(prometheus.Opts(entry.Opts), vecid, entry.Vec) {
+			return nil
+		}
+		reg.countervects[vecid] = entry
 	}
-	entry := cache_allcountervects[vecid]
+	entry := reg.countervects[vecid]
 	if strSliceCompare(entry.Labels, labelNames) == false {
 		Logger.Warn("id:%s cached counter vec labels dont match %v != %v", vecid, entry.Labels, labelNames)
 		return nil
 	}
 	valid := m.getFullName(prometheus.Opts(entry.Opts), labelValues)
-	if _, ok := cache_allcounters[valid]; !ok {
+	if _, ok := reg.counters[valid]; !ok {
 		Logger.Debug("Register new counter from vector with opts: %v labelValues: %v", entry.Opts, labelValues)
-		cache_allcounters[valid] = entry.Vec.WithLabelValues(labelValues...)
+		reg.counters[valid] = entry.Vec.WithLabelValues(labelValues...)
+		reg.addChild(prometheus.Opts(entry.Opts), valid)
 	}
-	return cache_allcounters[valid]
+	return reg.counters[valid]
 }
 
 //
@@ -404,20 +407,25 @@ func (m *Metrics) RegisterLabeledCounterGroup(optsgroup []CounterOpts, labelName
 //
 //
 func (m *Metrics) RegisterGauge(opts CounterOpts, subsytem string) Gauge {
-	globalLock.Lock()
-	defer globalLock.Unlock()
+	reg := m.getReg()
+	reg.Lock()
+	defer reg.Unlock()
 	opts.Namespace = m.Namespace
 	opts.Subsystem = subsytem
 	id := m.getFullName(prometheus.Opts(opts), []string{})
-	if _, ok := cache_allgaugevects[id]; ok {
+	if _, ok := reg.gaugevects[id]; ok {
 		Logger.Warn("Register new gauge with opts: %v, name conflicts existing gauge vector", opts)
 		return nil
 	}
-	if _, ok := cache_allgauges[id]; !ok {
+	if _, ok := reg.gauges[id]; !ok {
 		Logger.Debug("Register new gauge with opts: %v", opts)
-		cache_allgauges[id] = promauto.NewGauge(prometheus.GaugeOpts(opts))
+		g := prometheus.NewGauge(prometheus.GaugeOpts(opts))
+		if !reg.register(prometheus.Opts(opts), id, g) {
+			return nil
+		}
+		reg.gauges[id] = g
 	}
-	return cache_allgauges[id]
+	return reg.gauges[id]
 }
 
 //
@@ -435,34 +443,39 @@ func (m *Metrics) RegisterGaugeGroup(optsgroup []CounterOpts, subsytem string) m
 //
 //
 func (m *Metrics) RegisterLabeledGauge(opts CounterOpts, labelNames []string, labelValues []string, subsytem string) Gauge {
-	globalLock.Lock()
-	defer globalLock.Unlock()
+	reg := m.getReg()
+	reg.Lock()
+	defer reg.Unlock()
 	opts.Namespace = m.Namespace
 	opts.Subsystem = subsytem
 	vecid := m.getFullName(prometheus.Opts(opts), []string{})
-	if _, ok := cache_allgauges[vecid]; ok {
+	if _, ok := reg.gauges[vecid]; ok {
 		Logger.Warn("Register new gauge vector with opts: %v labelNames: %v, name conflicts existing counter", opts, labelNames)
 		return nil
 	}
-	if _, ok := cache_allgaugevects[vecid]; !ok {
+	if _, ok := reg.gaugevects[vecid]; !ok {
 		Logger.Debug("Register new gauge vector with opts: %v labelNames: %v", opts, labelNames)
 		entry := GaugeVec{}
 		entry.Opts = opts
 		entry.Labels = labelNames
-		entry.Vec = promauto.NewGaugeVec(prometheus.GaugeOpts(entry.Opts), entry.Labels)
-		cache_allgaugevects[vecid] = entry
+		entry.Vec = prometheus.NewGaugeVec(prometheus.GaugeOpts(entry.Opts), entry.Labels)
+		if !reg.register(prometheus.Opts(entry.Opts), vecid, entry.Vec) {
+			return nil
+		}
+		reg.gaugevects[vecid] = entry
 	}
-	entry := cache_allgaugevects[vecid]
+	entry := reg.gaugevects[vecid]
 	if strSliceCompare(entry.Labels, labelNames) == false {
 		Logger.Warn("id:%s cached gauge vec labels dont match %v != %v", vecid, entry.Labels, labelNames)
 		return nil
 	}
 	valid := m.getFullName(prometheus.Opts(entry.Opts), labelValues)
-	if _, ok := cache_allgauges[valid]; !ok {
+	if _, ok := reg.gauges[valid]; !ok {
 		Logger.Debug("Register new gauge from vector with opts: %v labelValues: %v", entry.Opts, labelValues)
-		cache_allgauges[valid] = entry.Vec.WithLabelValues(labelValues...)
+		reg.gauges[valid] = entry.Vec.WithLabelValues(labelValues...)
+		reg.addChild(prometheus.Opts(entry.Opts), valid)
 	}
-	return cache_allgauges[valid]
+	return reg.gauges[valid]
 }
 
 //
@@ -0,0 +1,347 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// -----------------------------------------------------------------------------
+// Alias
+// -----------------------------------------------------------------------------
+type HistogramOpts prometheus.HistogramOpts
+type SummaryOpts prometheus.SummaryOpts
+type Histogram prometheus.Observer
+type Summary prometheus.Observer
+
+type HistogramVec struct {
+	Vec    *prometheus.HistogramVec
+	Opts   HistogramOpts
+	Labels []string
+	series *metricSeries
+}
+
+type SummaryVec struct {
+	Vec    *prometheus.SummaryVec
+	Opts   SummaryOpts
+	Labels []string
+	series *metricSeries
+}
+
+// DefaultSummaryObjectives are the quantiles of a summary registered without Objectives
+var DefaultSummaryObjectives = map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001}
+
+// -----------------------------------------------------------------------------
+//
+// -----------------------------------------------------------------------------
+type MetricGroupsCacheHistogramRegisterer interface {
+	RegisterHistogram(HistogramOpts) Histogram
+}
+
+type MetricGroupsCacheHistogramRegistererFunc func(HistogramOpts) Histogram
+
+func (fn MetricGroupsCacheHistogramRegistererFunc) RegisterHistogram(hopts HistogramOpts) Histogram {
+	return fn(hopts)
+}
+
+// -----------------------------------------------------------------------------
+//
+// -----------------------------------------------------------------------------
+type MetricGroupsCacheSummaryRegisterer interface {
+	RegisterSummary(SummaryOpts) Summary
+}
+
+type MetricGroupsCacheSummaryRegistererFunc func(SummaryOpts) Summary
+
+func (fn MetricGroupsCacheSummaryRegistererFunc) RegisterSummary(sopts SummaryOpts) Summary {
+	return fn(sopts)
+}
+
+// -----------------------------------------------------------------------------
+// MetricGroupsCache: histograms and summaries
+// -----------------------------------------------------------------------------
+func (met *MetricGroupsCache) ObserverRegisterer(reghis MetricGroupsCacheHistogramRegisterer, regsum MetricGroupsCacheSummaryRegisterer) {
+	met.reghis = reghis
+	met.regsum = regsum
+}
+
+func (met *MetricGroupsCache) hReg(metric string) Histogram {
+	if met.reghis != nil {
+		hist := met.reghis.RegisterHistogram(HistogramOpts{Name: metric, Help: "Distribution of " + metric + "(auto)"})
+		met.histograms[metric] = hist
+		return hist
+	}
+	return nil
+}
+
+func (met *MetricGroupsCache) sReg(metric string) Summary {
+	if met.regsum != nil {
+		summ := met.regsum.RegisterSummary(SummaryOpts{Name: metric, Help: "Distribution of " + metric + "(auto)"})
+		met.summaries[metric] = summ
+		return summ
+	}
+	return nil
+}
+
+func (met *MetricGroupsCache) HIs(metric string) bool {
+	met.Lock()
+	defer met.Unlock()
+	_, ok := met.histograms[metric]
+	return ok
+}
+
+func (met *MetricGroupsCache) HGet(metric string) Histogram {
+	met.Lock()
+	defer met.Unlock()
+	hist, ok := met.histograms[metric]
+	if !ok {
+		hist = met.hReg(metric)
+	}
+	return hist
+}
+
+func (met *MetricGroupsCache) HObserve(metric string, val float64) {
+	met.Lock()
+	defer met.Unlock()
+	hist, ok := met.histograms[metric]
+	if !ok {
+		hist = met.hReg(metric)
+	}
+	hist.Observe(val)
+}
+
+func (met *MetricGroupsCache) SIs(metric string) bool {
+	met.Lock()
+	defer met.Unlock()
+	_, ok := met.summaries[metric]
+	return ok
+}
+
+func (met *MetricGroupsCache) SGet(metric string) Summary {
+	met.Lock()
+	defer met.Unlock()
+	summ, ok := met.summaries[metric]
+	if !ok {
+		summ = met.sReg(metric)
+	}
+	return summ
+}
+
+func (met *MetricGroupsCache) SObserve(metric string, val float64) {
+	met.Lock()
+	defer met.Unlock()
+	summ, ok := met.summaries[metric]
+	if !ok {
+		summ = met.sReg(metric)
+	}
+	summ.Observe(val)
+}
+
+func (met *MetricGroupsCache) CombineHistogramGroupsWithPrefix(prefix string, srcs ...map[string]Histogram) {
+	met.Lock()
+	defer met.Unlock()
+	for _, src := range srcs {
+		for k, v := range src {
+			met.histograms[prefix+k] = v
+		}
+	}
+}
+
+func (met *MetricGroupsCache) CombineHistogramGroups(srcs ...map[string]Histogram) {
+	met.CombineHistogramGroupsWithPrefix("", srcs...)
+}
+
+func (met *MetricGroupsCache) CombineSummaryGroupsWithPrefix(prefix string, srcs ...map[string]Summary) {
+	met.Lock()
+	defer met.Unlock()
+	for _, src := range srcs {
+		for k, v := range src {
+			met.summaries[prefix+k] = v
+		}
+	}
+}
+
+func (met *MetricGroupsCache) CombineSummaryGroups(srcs ...map[string]Summary) {
+	met.CombineSummaryGroupsWithPrefix("", srcs...)
+}
+
+// -----------------------------------------------------------------------------
+// Histograms/summaries registered via Metrics instances, with the same naming
+// as the counters and gauges
+// -----------------------------------------------------------------------------
+func observerOpts(namespace, subsystem, name string) prometheus.Opts {
+	return prometheus.Opts{Namespace: namespace, Subsystem: subsystem, Name: name}
+}
+
+// RegisterHistogram registers a histogram, prometheus.DefBuckets are used if opts has no Buckets
+func (m *Metrics) RegisterHistogram(opts HistogramOpts, subsytem string) Histogram {
+	reg := m.getReg()
+	reg.Lock()
+	defer reg.Unlock()
+	opts.Namespace = m.Namespace
+	opts.Subsystem = subsytem
+	id := m.getFullName(observerOpts(opts.Namespace, opts.Subsystem, opts.Name), []string{})
+	if _, ok := reg.histogramvects[id]; ok {
+		Logger.Warn("Register new histogram with opts: %v, name conflicts existing histogram vector", opts)
+		return nil
+	}
+	if _, ok := reg.histograms[id]; !ok {
+		Logger.Debug("Register new histogram with opts: %v", opts)
+		h := prometheus.NewHistogram(prometheus.HistogramOpts(opts))
+		if !reg.register(observerOpts(opts.Namespace, opts.Subsystem, opts.Name), id, h) {
+			return nil
+		}
+		reg.histograms[id] = h
+	}
+	return reg.histograms[id]
+}
+
+func (m *Metrics) RegisterHistogramGroup(optsgroup []HistogramOpts, subsytem string) map[string]Histogram {
+	c := make(map[string]Histogram)
+	for _, opts := range optsgroup {
+		c[opts.Name] = m.RegisterHistogram(opts, subsytem)
+	}
+	return c
+}
+
+func (m *Metrics) RegisterLabeledHistogram(opts HistogramOpts, labelNames []string, labelValues []string, subsytem string) Histogram {
+	reg := m.getReg()
+	reg.Lock()
+	defer reg.Unlock()
+	opts.Namespace = m.Namespace
+	opts.Subsystem = subsytem
+	vecid := m.getFullName(observerOpts(opts.Namespace, opts.Subsystem, opts.Name), []string{})
+	if _, ok := reg.histograms[vecid]; ok {
+		Logger.Warn("Register new histogram vector with opts: %v labelNames: %v, name conflicts existing histogram", opts, labelNames)
+		return nil
+	}
+	if _, ok := reg.histogramvects[vecid]; !ok {
+		Logger.Debug("Register new histogram vector with opts: %v labelNames: %v", opts, labelNames)
+		entry := HistogramVec{}
+		entry.Opts = opts
+		entry.Labels = labelNames
+		entry.Vec = prometheus.NewHistogramVec(prometheus.HistogramOpts(entry.Opts), entry.Labels)
+		if !reg.register(observerOpts(entry.Opts.Namespace, entry.Opts.Subsystem, entry.Opts.Name), vecid, entry.Vec) {
+			return nil
+		}
+		reg.histogramvects[vecid] = entry
+	}
+	entry := reg.histogramvects[vecid]
+	if !strSliceCompare(entry.Labels, labelNames) {
+		Logger.Warn("id:%s cached histogram vec labels dont match %v != %v", vecid, entry.Labels, labelNames)
+		return nil
+	}
+	valid := m.getFullName(observerOpts(entry.Opts.Namespace, entry.Opts.Subsystem, entry.Opts.Name), labelValues)
+	if _, ok := reg.histograms[valid]; !ok {
+		Logger.Debug("Register new histogram from vector with opts: %v labelValues: %v", entry.Opts, labelValues)
+		reg.histograms[valid] = entry.Vec.WithLabelValues(labelValues...)
+		reg.addChild(observerOpts(entry.Opts.Namespace, entry.Opts.Subsystem, entry.Opts.Name), valid)
+	}
+	return reg.histograms[valid]
+}
+
+func (m *Metrics) RegisterLabeledHistogramGroup(optsgroup []HistogramOpts, labelNames []string, labelValues []string, subsytem string) map[string]Histogram {
+	c := make(map[string]Histogram)
+	for _, opts := range optsgroup {
+		c[opts.Name] = m.RegisterLabeledHistogram(opts, labelNames, labelValues, subsytem)
+	}
+	return c
+}
+
+// RegisterSummary registers a summary, DefaultSummaryObjectives are used if opts has no Objectives
+func (m *Metrics) RegisterSummary(opts SummaryOpts, subsytem string) Summary {
+	reg := m.getReg()
+	reg.Lock()
+	defer reg.Unlock()
+	opts.Namespace = m.Namespace
+	opts.Subsystem = subsytem
+	if opts.Objectives == nil {
+		opts.Objectives = DefaultSummaryObjectives
+	}
+	id := m.getFullName(observerOpts(opts.Namespace, opts.Subsystem, opts.Name), []string{})
+	if _, ok := reg.summaryvects[id]; ok {
+		Logger.Warn("Register new summary with opts: %v, name conflicts existing summary vector", opts)
+		return nil
+	}
+	if _, ok := reg.summaries[id]; !ok {
+		Logger.Debug("Register new summary with opts: %v", opts)
+		s := prometheus.NewSummary(prometheus.SummaryOpts(opts))
+		if !reg.register(observerOpts(opts.Namespace, opts.Subsystem, opts.Name), id, s) {
+			return nil
+		}
+		reg.summaries[id] = s
+	}
+	return reg.summaries[id]
+}
+
+func (m *Metrics) RegisterSummaryGroup(optsgroup []SummaryOpts, subsytem string) map[string]Summary {
+	c := make(map[string]Summary)
+	for _, opts := range optsgroup {
+		c[opts.Name] = m.RegisterSummary(opts, subsytem)
+	}
+	return c
+}
+
+func (m *Metrics) RegisterLabeledSummary(opts SummaryOpts, labelNames []string, labelValues []string, subsytem string) Summary {
+	reg := m.getReg()
+	reg.Lock()
+	defer reg.Unlock()
+	opts.Namespace = m.Namespace
+	opts.Subsystem = subsytem
+	if opts.Objectives == nil {
+		opts.Objectives = DefaultSummaryObjectives
+	}
+	vecid := m.getFullName(observerOpts(opts.Namespace, opts.Subsystem, opts.Name), []string{})
+	if _, ok := reg.summaries[vecid]; ok {
+		Logger.Warn("Register new summary vector with opts: %v labelNames: %v, name conflicts existing summary", opts, labelNames)
+		return nil
+	}
+	if _, ok := reg.summaryvects[vecid]; !ok {
+		Logger.Debug("Register new summary vector with opts: %v labelNames: %v", opts, labelNames)
+		entry := SummaryVec{}
+		entry.Opts = opts
+		entry.Labels = labelNames
+		entry.Vec = prometheus.NewSummaryVec(prometheus.SummaryOpts(entry.Opts), entry.Labels)
+		if !reg.register(observerOpts(entry.Opts.Namespace, entry.Opts.Subsystem, entry.Opts.Name), vecid, entry.Vec) {
+			return nil
+		}
+		reg.summaryvects[vecid] = entry
+	}
+	entry := reg.summaryvects[vecid]
+	if !strSliceCompare(entry.Labels, labelNames) {
+		Logger.Warn("id:%s cached summary vec labels dont match %v != %v", vecid, entry.Labels, labelNames)
+		return nil
+	}
+	valid := m.getFullName(observerOpts(entry.Opts.Namespace, entry.Opts.Subsystem, entry.Opts.Name), labelValues)
+	if _, ok := reg.summaries[valid]; !ok {
+		Logger.Debug("Register new summary from vector with opts: %v labelValues: %v", entry.Opts, labelValues)
+		reg.summaries[valid] = entry.Vec.WithLabelValues(labelValues...)
+		reg.addChild(observerOpts(entry.Opts.Namespace, entry.Opts.Subsystem, entry.Opts.Name), valid)
+	}
+	return reg.summaries[valid]
+}
+
+func (m *Metrics) RegisterLabeledSummaryGroup(optsgroup []SummaryOpts, labelNames []string, labelValues []string, subsytem string) map[string]Summary {
+	c := make(map[string]Summary)
+	for _, opts := range optsgroup {
+		c[opts.Name] = m.RegisterLabeledSummary(opts, labelNames, labelValues, subsytem)
+	}
+	return c
+}
@@ -0,0 +1,120 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricHistogram(t *testing.T) {
+	opts := HistogramOpts{Name: "LoopLatency", Help: "Control loop latency", Buckets: []float64{0.01, 0.1, 1}}
+	ret1 := Metric.RegisterHistogram(opts, "TestMetricHistogram")
+	ret1.Observe(0.05)
+	ret2 := Metric.RegisterHistogram(opts, "TestMetricHistogram")
+	if ret1 != ret2 {
+		t.Errorf("ret1 not same than ret2. cache not working?")
+	}
+
+	group := Metric.RegisterHistogramGroup([]HistogramOpts{opts, {Name: "Other", Help: "Other"}}, "TestMetricHistogram")
+	assert.Equal(t, ret1, group["LoopLatency"])
+	assert.NotNil(t, group["Other"])
+
+	metrics, err := Resource.GetLocalMetrics()
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(metrics, `ricxapp_TestMetricHistogram_LoopLatency_bucket{le="0.1"} 1`))
+}
+
+func TestMetricLabeledHistogram(t *testing.T) {
+	opts := HistogramOpts{Name: "HistBlaah1", Help: "HistBlaah1"}
+	ret1 := Metric.RegisterLabeledHistogram(opts, []string{"loop"}, []string{"a"}, "TestMetricLabeledHistogram")
+	ret2 := Metric.RegisterLabeledHistogram(opts, []string{"loop"}, []string{"a"}, "TestMetricLabeledHistogram")
+	ret3 := Metric.RegisterLabeledHistogram(opts, []string{"loop"}, []string{"b"}, "TestMetricLabeledHistogram")
+	assert.NotNil(t, ret1)
+	assert.Equal(t, ret1, ret2)
+	assert.NotEqual(t, ret1, ret3)
+	ret3.Observe(1)
+
+	assert.Nil(t, Metric.RegisterLabeledHistogram(opts, []string{"other"}, []string{"a"}, "TestMetricLabeledHistogram"))
+	assert.Nil(t, Metric.RegisterHistogram(opts, "TestMetricLabeledHistogram"))
+
+	group := Metric.RegisterLabeledHistogramGroup([]HistogramOpts{opts}, []string{"loop"}, []string{"a"}, "TestMetricLabeledHistogram")
+	assert.Equal(t, ret1, group["HistBlaah1"])
+}
+
+func TestMetricSummary(t *testing.T) {
+	opts := SummaryOpts{Name: "SummBlaah1", Help: "SummBlaah1", Objectives: map[float64]float64{0.95: 0.01}}
+	ret1 := Metric.RegisterSummary(opts, "TestMetricSummary")
+	ret1.Observe(3)
+	assert.Equal(t, ret1, Metric.RegisterSummary(opts, "TestMetricSummary"))
+	assert.NotNil(t, Metric.RegisterSummaryGroup([]SummaryOpts{{Name: "SummBlaah2", Help: "SummBlaah2"}}, "TestMetricSummary")["SummBlaah2"])
+
+	metrics, _ := Resource.GetLocalMetrics()
+	assert.True(t, strings.Contains(metrics, `ricxapp_TestMetricSummary_SummBlaah1{quantile="0.95"} 3`))
+	assert.True(t, strings.Contains(metrics, `ricxapp_TestMetricSummary_SummBlaah2{quantile="0.99"}`))
+
+	lopts := SummaryOpts{Name: "SummBlaah3", Help: "SummBlaah3"}
+	ret2 := Metric.RegisterLabeledSummary(lopts, []string{"loop"}, []string{"a"}, "TestMetricSummary")
+	assert.NotNil(t, ret2)
+	assert.Equal(t, ret2, Metric.RegisterLabeledSummaryGroup([]SummaryOpts{lopts}, []string{"loop"}, []string{"a"}, "TestMetricSummary")["SummBlaah3"])
+	assert.Nil(t, Metric.RegisterSummary(lopts, "TestMetricSummary"))
+	assert.Nil(t, Metric.RegisterLabeledSummary(opts, []string{"loop"}, []string{"a"}, "TestMetricSummary"))
+}
+
+func (met *registerer) RegisterHistogram(opts HistogramOpts) Histogram {
+	return Metric.RegisterLabeledHistogram(
+		opts,
+		[]string{"host", "interface"},
+		[]string{"testhost", "testinterface"},
+		"SUBSYSTEMAUTO")
+}
+
+func (met *registerer) RegisterSummary(opts SummaryOpts) Summary {
+	return Metric.RegisterLabeledSummary(
+		opts,
+		[]string{"host", "interface"},
+		[]string{"testhost", "testinterface"},
+		"SUBSYSTEMAUTO")
+}
+
+func TestMetricGroupCacheObservers(t *testing.T) {
+	m_grp := NewMetricGroupsCache()
+	assert.Nil(t, m_grp.HGet("hautotest1"))
+	assert.Nil(t, m_grp.SGet("sautotest1"))
+
+	m_reg := &registerer{}
+	m_grp.ObserverRegisterer(MetricGroupsCacheHistogramRegistererFunc(m_reg.RegisterHistogram), m_reg)
+	assert.False(t, m_grp.HIs("hautotest1"))
+	m_grp.HObserve("hautotest1", 1)
+	assert.True(t, m_grp.HIs("hautotest1"))
+	m_grp.SObserve("sautotest1", 1)
+	assert.True(t, m_grp.SIs("sautotest1"))
+
+	hists := Metric.RegisterHistogramGroup([]HistogramOpts{{Name: "HGrp1", Help: "HGrp1"}}, "TestMetricGroupCacheObservers")
+	summs := Metric.RegisterSummaryGroup([]SummaryOpts{{Name: "SGrp1", Help: "SGrp1"}}, "TestMetricGroupCacheObservers")
+	m_grp.CombineHistogramGroupsWithPrefix("event1_", hists)
+	m_grp.CombineSummaryGroups(summs)
+	assert.True(t, m_grp.HIs("event1_HGrp1"))
+	assert.True(t, m_grp.SIs("SGrp1"))
+	m_grp.HObserve("event1_HGrp1", 0.5)
+	m_grp.SObserve("SGrp1", 0.5)
+}
@@ -0,0 +1,240 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// metricsRegistry is the prometheus registry of a Metrics instance with the metrics
+// registered via it. The metrics are cached by ids built from: namespace, subsystem,
+// metric and possible labels.
+type metricsRegistry struct {
+	sync.Mutex
+	registry       *prometheus.Registry
+	collectors     map[string]*metricCollector // By fully-qualified name
+	counters       map[string]Counter
+	gauges         map[string]Gauge
+	countervects   map[string]CounterVec
+	gaugevects     map[string]GaugeVec
+	histograms     map[string]Histogram
+	summaries      map[string]Summary
+	histogramvects map[string]HistogramVec
+	summaryvects   map[string]SummaryVec
+
+	measurementsLock sync.Mutex
+	declared         map[string]declaredMetric // By name
+	measurements     []Measurement
+}
+
+// metricCollector is a registered metric or vector, ids are the cache ids of it
+// and of the metrics of the vector
+type metricCollector struct {
+	collector prometheus.Collector
+	ids       []string
+}
+
+func newMetricsRegistry() *metricsRegistry {
+	return &metricsRegistry{
+		registry:       prometheus.NewRegistry(),
+		collectors:     make(map[string]*metricCollector),
+		counters:       make(map[string]Counter),
+		gauges:         make(map[string]Gauge),
+		countervects:   make(map[string]CounterVec),
+		gaugevects:     make(map[string]GaugeVec),
+		histograms:     make(map[string]Histogram),
+		summaries:      make(map[string]Summary),
+		histogramvects: make(map[string]HistogramVec),
+		summaryvects:   make(map[string]SummaryVec),
+		declared:       make(map[string]declaredMetric),
+	}
+}
+
+// metricsRegMux guards the creation of the registry of a Metrics not made with NewMetrics
+var metricsRegMux sync.Mutex
+
+// getReg returns the registry of the metrics, it is created on first use for a Metrics
+// made without NewMetrics, e.g. &Metrics{Namespace: "ricxapp"}
+func (m *Metrics) getReg() *metricsRegistry {
+	metricsRegMux.Lock()
+	defer metricsRegMux.Unlock()
+	if m.reg == nil {
+		m.reg = newMetricsRegistry()
+	}
+	return m.reg
+}
+
+// Registry returns the prometheus registry of the metrics, e.g. for own collectors
+func (m *Metrics) Registry() *prometheus.Registry {
+	reg := m.getReg()
+	reg.Lock()
+	defer reg.Unlock()
+	return reg.registry
+}
+
+// Gatherer returns the metrics served on the metrics URL: the registry of the metrics
+// and the prometheus default registry, which has the Go runtime and process metrics
+func (m *Metrics) Gatherer() prometheus.Gatherer {
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		return prometheus.Gatherers{m.Registry(), prometheus.DefaultGatherer}.Gather()
+	})
+}
+
+// Unregister removes a metric or a vector by its exported name, e.g. "ricxapp_SDL_Stored".
+// The handles returned for it are no longer exported, registering it again creates a
+// new one. Prometheus requires it to have the same labels and help as before, otherwise
+// nil is returned.
+func (m *Metrics) Unregister(name string) bool {
+	reg := m.getReg()
+	reg.Lock()
+	defer reg.Unlock()
+	return reg.unregister(name)
+}
+
+// Reset replaces the registry with an empty one, e.g. between tests. The handles
+// returned so far, the declared measurements and own collectors registered to Registry
+// are dropped.
+func (m *Metrics) Reset() {
+	reg := m.getReg()
+	reg.Lock()
+	defer reg.Unlock()
+	for name := range reg.collectors {
+		reg.unregister(name)
+	}
+	reg.registry = prometheus.NewRegistry()
+
+	reg.measurementsLock.Lock()
+	defer reg.measurementsLock.Unlock()
+	reg.measurements = nil
+}
+
+// register registers a new metric or vector cached under id, the lock must be held. A
+// conflict with a metric of the prometheus registry, e.g. of another type or with other
+// labels or help, is logged and false returned.
+func (r *metricsRegistry) register(opts prometheus.Opts, id string, c prometheus.Collector) bool {
+	fqName := prometheus.BuildFQName(opts.Namespace, opts.Subsystem, opts.Name)
+	if err := r.registry.Register(c); err != nil {
+		Logger.Warn("id:%s registering metric %s failed: %v", id, fqName, err)
+		return false
+	}
+	r.collectors[fqName] = &metricCollector{collector: c, ids: []string{id}}
+	return true
+}
+
+// addChild records the cache id of a metric of a vector, the lock must be held
+func (r *metricsRegistry) addChild(opts prometheus.Opts, id string) {
+	if c, ok := r.collectors[prometheus.BuildFQName(opts.Namespace, opts.Subsystem, opts.Name)]; ok {
+		c.ids = append(c.ids, id)
+	}
+}
+
+// unregister removes the metric of the fully-qualified name, the lock must be held
+func (r *metricsRegistry) unregister(fqName string) bool {
+	c, ok := r.collectors[fqName]
+	if !ok {
+		return false
+	}
+	r.registry.Unregister(c.collector)
+	for _, id := range c.ids {
+		r.forget(id)
+	}
+	delete(r.collectors, fqName)
+
+	r.measurementsLock.Lock()
+	defer r.measurementsLock.Unlock()
+	delete(r.declared, fqName)
+	for i, meas := range r.measurements {
+		metrics := []MeasurementMetric{}
+		for _, mm := range meas.Metrics {
+			if mm.Name != fqName {
+				metrics = append(metrics, mm)
+			}
+		}
+		r.measurements[i].Metrics = metrics
+	}
+	return true
+}
+
+// cachedKind returns the kind of the metric cached under id, e.g. "counter vector",
+// an empty string if there is none. The lock is taken.
+func (r *metricsRegistry) cachedKind(id string) string {
+	r.Lock()
+	defer r.Unlock()
+	switch {
+	case r.counters[id] != nil:
+		return MeasurementTypeCounter
+	case r.gauges[id] != nil:
+		return MeasurementTypeGauge
+	case r.histograms[id] != nil:
+		return MeasurementTypeHistogram
+	case r.summaries[id] != nil:
+		return "summary"
+	}
+	if _, ok := r.countervects[id]; ok {
+		return MeasurementTypeCounter + " vector"
+	}
+	if _, ok := r.gaugevects[id]; ok {
+		return MeasurementTypeGauge + " vector"
+	}
+	if _, ok := r.histogramvects[id]; ok {
+		return MeasurementTypeHistogram + " vector"
+	}
+	if _, ok := r.summaryvects[id]; ok {
+		return "summary vector"
+	}
+	return ""
+}
+
+// cached tells if a metric is cached under id, the lock is taken
+func (r *metricsRegistry) cached(id string) bool {
+	r.Lock()
+	defer r.Unlock()
+	_, counter := r.counters[id]
+	_, gauge := r.gauges[id]
+	_, histogram := r.histograms[id]
+	_, summary := r.summaries[id]
+	return counter || gauge || histogram || summary
+}
+
+func (r *metricsRegistry) forget(id string) {
+	if v, ok := r.countervects[id]; ok {
+		v.series.close()
+	}
+	if v, ok := r.gaugevects[id]; ok {
+		v.series.close()
+	}
+	if v, ok := r.histogramvects[id]; ok {
+		v.series.close()
+	}
+	if v, ok := r.summaryvects[id]; ok {
+		v.series.close()
+	}
+	delete(r.counters, id)
+	delete(r.gauges, id)
+	delete(r.countervects, id)
+	delete(r.gaugevects, id)
+	delete(r.histograms, id)
+	delete(r.summaries, id)
+	delete(r.histogramvects, id)
+	delete(r.summaryvects, id)
+}
@@ -0,0 +1,134 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func scrapeMetrics(t *testing.T, r *mux.Router, url string) string {
+	req, _ := http.NewRequest("GET", url, nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	body, _ := ioutil.ReadAll(rr.Body)
+	return string(body)
+}
+
+func TestMetricsIsolatedRegistries(t *testing.T) {
+	r1, r2 := mux.NewRouter(), mux.NewRouter()
+	m1 := NewMetrics("/metrics", "ricxapp", r1)
+	m2 := NewMetrics("/metrics", "ricxapp", r2)
+
+	opts := CounterOpts{Name: "Requests", Help: "Requests"}
+	c1 := m1.RegisterCounter(opts, "TestMetricsIsolatedRegistries")
+	c2 := m2.RegisterCounter(opts, "TestMetricsIsolatedRegistries")
+	assert.NotEqual(t, c1, c2)
+	c1.Inc()
+	c2.Add(5)
+
+	body := scrapeMetrics(t, r1, "/metrics")
+	assert.True(t, strings.Contains(body, "ricxapp_TestMetricsIsolatedRegistries_Requests 1"))
+	assert.True(t, strings.Contains(body, "go_goroutines"))
+	assert.True(t, strings.Contains(scrapeMetrics(t, r2, "/metrics"), "ricxapp_TestMetricsIsolatedRegistries_Requests 5"))
+
+	// Not in the registry of Metric
+	metrics, err := Resource.GetLocalMetrics()
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(metrics, "TestMetricsIsolatedRegistries"))
+	assert.Equal(t, 1, testutil.CollectAndCount(m1.Registry(), "ricxapp_TestMetricsIsolatedRegistries_Requests"))
+}
+
+func TestMetricsUnregister(t *testing.T) {
+	m := NewMetrics("/metrics", "ricxapp", mux.NewRouter())
+	opts := CounterOpts{Name: "Requests", Help: "Requests"}
+	c := m.RegisterCounter(opts, "TestMetricsUnregister")
+	c.Add(3)
+	lc := m.RegisterLabeledCounter(CounterOpts{Name: "Errors", Help: "Errors"}, []string{"cause"}, []string{"timeout"}, "TestMetricsUnregister")
+	lc.Inc()
+	vec := m.RegisterGaugeVec(CounterOpts{Name: "Load", Help: "Load"}, []string{"cell"}, "TestMetricsUnregister", VecOpts{})
+	vec.WithLabelValues("c1").Set(1)
+
+	assert.True(t, m.Unregister("ricxapp_TestMetricsUnregister_Requests"))
+	assert.False(t, m.Unregister("ricxapp_TestMetricsUnregister_Requests"))
+	assert.True(t, m.Unregister("ricxapp_TestMetricsUnregister_Errors"))
+	assert.Equal(t, 0, testutil.CollectAndCount(m.Registry(), "ricxapp_TestMetricsUnregister_Requests", "ricxapp_TestMetricsUnregister_Errors"))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.Registry(), "ricxapp_TestMetricsUnregister_Load"))
+
+	// Registered again from zero
+	c = m.RegisterCounter(opts, "TestMetricsUnregister")
+	assert.Equal(t, float64(0), testutil.ToFloat64(c))
+	lc = m.RegisterLabeledCounter(CounterOpts{Name: "Errors", Help: "Errors"}, []string{"cause"}, []string{"timeout"}, "TestMetricsUnregister")
+	assert.Equal(t, float64(0), testutil.ToFloat64(lc))
+}
+
+func TestMetricsReset(t *testing.T) {
+	m := NewMetrics("/metrics", "ricxapp", mux.NewRouter())
+	m.RegisterCounterGroup([]CounterOpts{{Name: "A", Help: "A"}, {Name: "B", Help: "B"}}, "TestMetricsReset")
+	m.RegisterHistogram(HistogramOpts{Name: "Latency", Help: "Latency"}, "TestMetricsReset")
+	m.RegisterSummaryVec(SummaryOpts{Name: "Delay", Help: "Delay"}, []string{"meid"}, "TestMetricsReset", VecOpts{IdleTimeout: 10})
+	m.RegisterMeasurements([]Measurement{{MoId: "m", Metrics: []MeasurementMetric{{Name: "ricxapp_TestMetricsReset_Declared", Type: MeasurementTypeGauge, Description: "Declared"}}}})
+	assert.NotNil(t, m.DeclaredGauge("ricxapp_TestMetricsReset_Declared"))
+
+	m.Reset()
+	mfs, err := m.Registry().Gather()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(mfs))
+	assert.Nil(t, m.DeclaredGauge("ricxapp_TestMetricsReset_Declared"))
+	assert.Equal(t, 0, len(m.Measurements()))
+
+	// The names are free for other labels and help
+	m.RegisterLabeledCounter(CounterOpts{Name: "A", Help: "Other"}, []string{"x"}, []string{"y"}, "TestMetricsReset").Inc()
+	assert.Equal(t, 1, testutil.CollectAndCount(m.Registry()))
+}
+
+func TestMetricsRegisterConflict(t *testing.T) {
+	m := NewMetrics("/metrics", "ricxapp", mux.NewRouter())
+	c := m.RegisterCounter(CounterOpts{Name: "Requests", Help: "Requests"}, "TestMetricsRegisterConflict_Sub")
+	assert.NotNil(t, c)
+
+	// The same fully-qualified name with another type
+	assert.Nil(t, m.RegisterGauge(CounterOpts{Name: "Sub_Requests", Help: "Requests"}, "TestMetricsRegisterConflict"))
+
+	// Registered again after Unregister with other help
+	vopts := CounterOpts{Name: "Errors", Help: "Errors"}
+	assert.NotNil(t, m.RegisterCounterVec(vopts, []string{"cause"}, "TestMetricsRegisterConflict", VecOpts{}))
+	assert.True(t, m.Unregister("ricxapp_TestMetricsRegisterConflict_Errors"))
+	vopts.Help = "Other"
+	assert.Nil(t, m.RegisterCounterVec(vopts, []string{"cause"}, "TestMetricsRegisterConflict", VecOpts{}))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.Registry()))
+}
+
+func TestMetricsZeroValue(t *testing.T) {
+	m := &Metrics{Namespace: "ricxapp"}
+	c := m.RegisterCounter(CounterOpts{Name: "Requests", Help: "Requests"}, "TestMetricsZeroValue")
+	assert.NotNil(t, c)
+	c.Inc()
+	assert.Equal(t, float64(1), testutil.ToFloat64(c))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.Registry(), "ricxapp_TestMetricsZeroValue_Requests"))
+}
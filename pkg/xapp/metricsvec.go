@@ -0,0 +1,434 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// VecOpts limits the series of a vector registered with RegisterCounterVec and friends.
+// A zero VecOpts sets no limits.
+type VecOpts struct {
+	MaxSeries   int           // Maximum number of label value combinations, 0 is unlimited
+	IdleTimeout time.Duration // Series not observed for this long are removed, 0 keeps them
+}
+
+// Returned over the series limit, they are not exported
+var (
+	discardedCounter   Counter   = prometheus.NewCounter(prometheus.CounterOpts{Name: "discarded"})
+	discardedGauge     Gauge     = prometheus.NewGauge(prometheus.GaugeOpts{Name: "discarded"})
+	discardedHistogram Histogram = prometheus.NewHistogram(prometheus.HistogramOpts{Name: "discarded"})
+	discardedSummary   Summary   = prometheus.NewSummary(prometheus.SummaryOpts{Name: "discarded"})
+)
+
+// minSweepInterval is the shortest interval of looking for idle series
+const minSweepInterval = time.Second
+
+// metricSeries tracks the label values used via a vector handle, a nil metricSeries
+// sets no limits
+type metricSeries struct {
+	sync.Mutex
+	id      string
+	opts    VecOpts
+	last    map[string]time.Time
+	values  map[string][]string
+	dropped uint64
+	warned  bool
+	delete  func(values []string) bool
+	fixed   func(values []string) bool // Cached by RegisterLabeledCounter and friends, not evicted
+	stop    chan struct{}
+}
+
+func newMetricSeries(id string, opts VecOpts, delete, fixed func(values []string) bool) *metricSeries {
+	s := &metricSeries{
+		id:     id,
+		opts:   opts,
+		last:   make(map[string]time.Time),
+		values: make(map[string][]string),
+		delete: delete,
+		fixed:  fixed,
+		stop:   make(chan struct{}),
+	}
+	if opts.IdleTimeout > 0 {
+		interval := opts.IdleTimeout / 2
+		if interval < minSweepInterval {
+			interval = minSweepInterval
+		}
+		go s.sweep(interval)
+	}
+	return s
+}
+
+func seriesKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+// use marks the label values as observed now, false if the values can't be used
+func (s *metricSeries) use(values []string, labels []string) bool {
+	if len(values) != len(labels) {
+		Logger.Warn("label values %v dont match labels %v", values, labels)
+		return false
+	}
+	if s == nil {
+		return true
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	key := seriesKey(values)
+	if _, ok := s.last[key]; !ok {
+		if s.opts.MaxSeries > 0 && len(s.last) >= s.opts.MaxSeries {
+			s.dropped++
+			if !s.warned {
+				Logger.Warn("id:%s series limit %d reached, dropping label values %v", s.id, s.opts.MaxSeries, values)
+				s.warned = true
+			}
+			return false
+		}
+		s.values[key] = append([]string{}, values...)
+	}
+	s.last[key] = time.Now()
+	return true
+}
+
+func (s *metricSeries) remove(values []string) {
+	if s == nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	key := seriesKey(values)
+	delete(s.last, key)
+	delete(s.values, key)
+	s.warned = false
+}
+
+// evict removes the series idle since before the idle timeout, returns the number removed.
+// A series also registered with RegisterLabeledCounter and friends is kept, its handle is cached.
+func (s *metricSeries) evict(now time.Time) int {
+	s.Lock()
+	defer s.Unlock()
+
+	n := 0
+	for key, last := range s.last {
+		if now.Sub(last) < s.opts.IdleTimeout || s.fixed(s.values[key]) {
+			continue
+		}
+		s.delete(s.values[key])
+		delete(s.last, key)
+		delete(s.values, key)
+		n++
+	}
+	if n > 0 {
+		Logger.Debug("id:%s evicted %d idle series", s.id, n)
+		s.warned = false
+	}
+	return n
+}
+
+func (s *metricSeries) sweep(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			s.evict(now)
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// close stops the sweep when the vector is unregistered
+func (s *metricSeries) close() {
+	if s != nil {
+		close(s.stop)
+	}
+}
+
+func (s *metricSeries) len() int {
+	if s == nil {
+		return 0
+	}
+	s.Lock()
+	defer s.Unlock()
+	return len(s.last)
+}
+
+func (s *metricSeries) droppedCount() uint64 {
+	if s == nil {
+		return 0
+	}
+	s.Lock()
+	defer s.Unlock()
+	return s.dropped
+}
+
+// -----------------------------------------------------------------------------
+// Vector handles, the label values are given at observation time
+// -----------------------------------------------------------------------------
+
+// RegisterCounterVec registers a counter vector, the label values are given with
+// CounterVec.WithLabelValues. The VecOpts of the first registration are used.
+func (m *Metrics) RegisterCounterVec(opts CounterOpts, labelNames []string, subsytem string, vopts VecOpts) *CounterVec {
+	reg := m.getReg()
+	reg.Lock()
+	defer reg.Unlock()
+	opts.Namespace = m.Namespace
+	opts.Subsystem = subsytem
+	vecid := m.getFullName(prometheus.Opts(opts), []string{})
+	if _, ok := reg.counters[vecid]; ok {
+		Logger.Warn("Register new counter vector with opts: %v labelNames: %v, name conflicts existing counter", opts, labelNames)
+		return nil
+	}
+	if _, ok := reg.countervects[vecid]; !ok {
+		Logger.Debug("Register new counter vector with opts: %v labelNames: %v", opts, labelNames)
+		entry := CounterVec{}
+		entry.Opts = opts
+		entry.Labels = labelNames
+		entry.Vec = prometheus.NewCounterVec(prometheus.CounterOpts(entry.Opts), entry.Labels)
+		if !reg.register(prometheus.Opts(entry.Opts), vecid, entry.Vec) {
+			return nil
+		}
+		reg.countervects[vecid] = entry
+	}
+	entry := reg.countervects[vecid]
+	if !strSliceCompare(entry.Labels, labelNames) {
+		Logger.Warn("id:%s cached counter vec labels dont match %v != %v", vecid, entry.Labels, labelNames)
+		return nil
+	}
+	if entry.series == nil {
+		entry.series = newMetricSeries(vecid, vopts,
+			func(values []string) bool { return entry.Vec.DeleteLabelValues(values...) },
+			func(values []string) bool { return reg.cached(m.getFullName(prometheus.Opts(entry.Opts), values)) })
+		reg.countervects[vecid] = entry
+	}
+	return &entry
+}
+
+// WithLabelValues returns the counter of the label values. Call it at observation time,
+// it keeps the series from being evicted. Over the series limit the returned counter
+// is not exported.
+func (v *CounterVec) WithLabelValues(values ...string) Counter {
+	if !v.series.use(values, v.Labels) {
+		return discardedCounter
+	}
+	return v.Vec.WithLabelValues(values...)
+}
+
+// DeleteLabelValues removes the series of the label values, e.g. when a RAN node is gone
+func (v *CounterVec) DeleteLabelValues(values ...string) bool {
+	v.series.remove(values)
+	return v.Vec.DeleteLabelValues(values...)
+}
+
+// Series returns the number of series created with WithLabelValues
+func (v *CounterVec) Series() int {
+	return v.series.len()
+}
+
+// Dropped returns the number of observations dropped over the series limit
+func (v *CounterVec) Dropped() uint64 {
+	return v.series.droppedCount()
+}
+
+// RegisterGaugeVec registers a gauge vector, see RegisterCounterVec
+func (m *Metrics) RegisterGaugeVec(opts CounterOpts, labelNames []string, subsytem string, vopts VecOpts) *GaugeVec {
+	reg := m.getReg()
+	reg.Lock()
+	defer reg.Unlock()
+	opts.Namespace = m.Namespace
+	opts.Subsystem = subsytem
+	vecid := m.getFullName(prometheus.Opts(opts), []string{})
+	if _, ok := reg.gauges[vecid]; ok {
+		Logger.Warn("Register new gauge vector with opts: %v labelNames: %v, name conflicts existing gauge", opts, labelNames)
+		return nil
+	}
+	if _, ok := reg.gaugevects[vecid]; !ok {
+		Logger.Debug("Register new gauge vector with opts: %v labelNames: %v", opts, labelNames)
+		entry := GaugeVec{}
+		entry.Opts = opts
+		entry.Labels = labelNames
+		entry.Vec = prometheus.NewGaugeVec(prometheus.GaugeOpts(entry.Opts), entry.Labels)
+		if !reg.register(prometheus.Opts(entry.Opts), vecid, entry.Vec) {
+			return nil
+		}
+		reg.gaugevects[vecid] = entry
+	}
+	entry := reg.gaugevects[vecid]
+	if !strSliceCompare(entry.Labels, labelNames) {
+		Logger.Warn("id:%s cached gauge vec labels dont match %v != %v", vecid, entry.Labels, labelNames)
+		return nil
+	}
+	if entry.series == nil {
+		entry.series = newMetricSeries(vecid, vopts,
+			func(values []string) bool { return entry.Vec.DeleteLabelValues(values...) },
+			func(values []string) bool { return reg.cached(m.getFullName(prometheus.Opts(entry.Opts), values)) })
+		reg.gaugevects[vecid] = entry
+	}
+	return &entry
+}
+
+// WithLabelValues returns the gauge of the label values, see CounterVec.WithLabelValues
+func (v *GaugeVec) WithLabelValues(values ...string) Gauge {
+	if !v.series.use(values, v.Labels) {
+		return discardedGauge
+	}
+	return v.Vec.WithLabelValues(values...)
+}
+
+func (v *GaugeVec) DeleteLabelValues(values ...string) bool {
+	v.series.remove(values)
+	return v.Vec.DeleteLabelValues(values...)
+}
+
+func (v *GaugeVec) Series() int {
+	return v.series.len()
+}
+
+func (v *GaugeVec) Dropped() uint64 {
+	return v.series.droppedCount()
+}
+
+// RegisterHistogramVec registers a histogram vector, see RegisterCounterVec
+func (m *Metrics) RegisterHistogramVec(opts HistogramOpts, labelNames []string, subsytem string, vopts VecOpts) *HistogramVec {
+	reg := m.getReg()
+	reg.Lock()
+	defer reg.Unlock()
+	opts.Namespace = m.Namespace
+	opts.Subsystem = subsytem
+	vecid := m.getFullName(observerOpts(opts.Namespace, opts.Subsystem, opts.Name), []string{})
+	if _, ok := reg.histograms[vecid]; ok {
+		Logger.Warn("Register new histogram vector with opts: %v labelNames: %v, name conflicts existing histogram", opts, labelNames)
+		return nil
+	}
+	if _, ok := reg.histogramvects[vecid]; !ok {
+		Logger.Debug("Register new histogram vector with opts: %v labelNames: %v", opts, labelNames)
+		entry := HistogramVec{}
+		entry.Opts = opts
+		entry.Labels = labelNames
+		entry.Vec = prometheus.NewHistogramVec(prometheus.HistogramOpts(entry.Opts), entry.Labels)
+		if !reg.register(observerOpts(entry.Opts.Namespace, entry.Opts.Subsystem, entry.Opts.Name), vecid, entry.Vec) {
+			return nil
+		}
+		reg.histogramvects[vecid] = entry
+	}
+	entry := reg.histogramvects[vecid]
+	if !strSliceCompare(entry.Labels, labelNames) {
+		Logger.Warn("id:%s cached histogram vec labels dont match %v != %v", vecid, entry.Labels, labelNames)
+		return nil
+	}
+	if entry.series == nil {
+		fqOpts := observerOpts(entry.Opts.Namespace, entry.Opts.Subsystem, entry.Opts.Name)
+		entry.series = newMetricSeries(vecid, vopts,
+			func(values []string) bool { return entry.Vec.DeleteLabelValues(values...) },
+			func(values []string) bool { return reg.cached(m.getFullName(fqOpts, values)) })
+		reg.histogramvects[vecid] = entry
+	}
+	return &entry
+}
+
+// WithLabelValues returns the histogram of the label values, see CounterVec.WithLabelValues
+func (v *HistogramVec) WithLabelValues(values ...string) Histogram {
+	if !v.series.use(values, v.Labels) {
+		return discardedHistogram
+	}
+	return v.Vec.WithLabelValues(values...)
+}
+
+func (v *HistogramVec) DeleteLabelValues(values ...string) bool {
+	v.series.remove(values)
+	return v.Vec.DeleteLabelValues(values...)
+}
+
+func (v *HistogramVec) Series() int {
+	return v.series.len()
+}
+
+func (v *HistogramVec) Dropped() uint64 {
+	return v.series.droppedCount()
+}
+
+// RegisterSummaryVec registers a summary vector, see RegisterCounterVec
+func (m *Metrics) RegisterSummaryVec(opts SummaryOpts, labelNames []string, subsytem string, vopts VecOpts) *SummaryVec {
+	reg := m.getReg()
+	reg.Lock()
+	defer reg.Unlock()
+	opts.Namespace = m.Namespace
+	opts.Subsystem = subsytem
+	if opts.Objectives == nil {
+		opts.Objectives = DefaultSummaryObjectives
+	}
+	vecid := m.getFullName(observerOpts(opts.Namespace, opts.Subsystem, opts.Name), []string{})
+	if _, ok := reg.summaries[vecid]; ok {
+		Logger.Warn("Register new summary vector with opts: %v labelNames: %v, name conflicts existing summary", opts, labelNames)
+		return nil
+	}
+	if _, ok := reg.summaryvects[vecid]; !ok {
+		Logger.Debug("Register new summary vector with opts: %v labelNames: %v", opts, labelNames)
+		entry := SummaryVec{}
+		entry.Opts = opts
+		entry.Labels = labelNames
+		entry.Vec = prometheus.NewSummaryVec(prometheus.SummaryOpts(entry.Opts), entry.Labels)
+		if !reg.register(observerOpts(entry.Opts.Namespace, entry.Opts.Subsystem, entry.Opts.Name), vecid, entry.Vec) {
+			return nil
+		}
+		reg.summaryvects[vecid] = entry
+	}
+	entry := reg.summaryvects[vecid]
+	if !strSliceCompare(entry.Labels, labelNames) {
+		Logger.Warn("id:%s cached summary vec labels dont match %v != %v", vecid, entry.Labels, labelNames)
+		return nil
+	}
+	if entry.series == nil {
+		fqOpts := observerOpts(entry.Opts.Namespace, entry.Opts.Subsystem, entry.Opts.Name)
+		entry.series = newMetricSeries(vecid, vopts,
+			func(values []string) bool { return entry.Vec.DeleteLabelValues(values...) },
+			func(values []string) bool { return reg.cached(m.getFullName(fqOpts, values)) })
+		reg.summaryvects[vecid] = entry
+	}
+	return &entry
+}
+
+// WithLabelValues returns the summary of the label values, see CounterVec.WithLabelValues
+func (v *SummaryVec) WithLabelValues(values ...string) Summary {
+	if !v.series.use(values, v.Labels) {
+		return discardedSummary
+	}
+	return v.Vec.WithLabelValues(values...)
+}
+
+func (v *SummaryVec) DeleteLabelValues(values ...string) bool {
+	v.series.remove(values)
+	return v.Vec.DeleteLabelValues(values...)
+}
+
+func (v *SummaryVec) Series() int {
+	return v.series.len()
+}
+
+func (v *SummaryVec) Dropped() uint64 {
+	return v.series.droppedCount()
+}
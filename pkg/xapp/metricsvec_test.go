@@ -0,0 +1,126 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricCounterVec(t *testing.T) {
+	opts := CounterOpts{Name: "RanMessages", Help: "Messages per RAN node"}
+	vec := Metric.RegisterCounterVec(opts, []string{"meid"}, "TestMetricCounterVec", VecOpts{})
+	assert.NotNil(t, vec)
+	vec.WithLabelValues("gnb1").Inc()
+	vec.WithLabelValues("gnb1").Inc()
+	vec.WithLabelValues("gnb2").Add(5)
+	assert.Equal(t, 2, vec.Series())
+
+	again := Metric.RegisterCounterVec(opts, []string{"meid"}, "TestMetricCounterVec", VecOpts{})
+	assert.Equal(t, vec.Vec, again.Vec)
+	assert.Equal(t, 2, again.Series())
+
+	// Shares the vector with the labeled counters
+	fixed := Metric.RegisterLabeledCounter(opts, []string{"meid"}, []string{"gnb1"}, "TestMetricCounterVec")
+	assert.Equal(t, vec.WithLabelValues("gnb1"), fixed)
+
+	assert.Nil(t, Metric.RegisterCounterVec(opts, []string{"cell"}, "TestMetricCounterVec", VecOpts{}))
+	assert.Nil(t, Metric.RegisterCounter(opts, "TestMetricCounterVec"))
+
+	metrics, err := Resource.GetLocalMetrics()
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(metrics, `ricxapp_TestMetricCounterVec_RanMessages{meid="gnb1"} 2`))
+	assert.True(t, strings.Contains(metrics, `ricxapp_TestMetricCounterVec_RanMessages{meid="gnb2"} 5`))
+
+	assert.True(t, vec.DeleteLabelValues("gnb2"))
+	assert.Equal(t, 1, vec.Series())
+	metrics, _ = Resource.GetLocalMetrics()
+	assert.False(t, strings.Contains(metrics, `ricxapp_TestMetricCounterVec_RanMessages{meid="gnb2"}`))
+
+	// Wrong number of label values
+	assert.Equal(t, discardedCounter, vec.WithLabelValues("gnb1", "cell1"))
+}
+
+func TestMetricVecLimit(t *testing.T) {
+	opts := CounterOpts{Name: "CellLoad", Help: "Load per cell"}
+	vec := Metric.RegisterGaugeVec(opts, []string{"cell"}, "TestMetricVecLimit", VecOpts{MaxSeries: 2})
+	vec.WithLabelValues("c1").Set(1)
+	vec.WithLabelValues("c2").Set(2)
+	vec.WithLabelValues("c3").Set(3)
+	vec.WithLabelValues("c3").Set(3)
+	vec.WithLabelValues("c1").Set(4)
+	assert.Equal(t, 2, vec.Series())
+	assert.Equal(t, uint64(2), vec.Dropped())
+
+	metrics, _ := Resource.GetLocalMetrics()
+	assert.True(t, strings.Contains(metrics, `ricxapp_TestMetricVecLimit_CellLoad{cell="c1"} 4`))
+	assert.False(t, strings.Contains(metrics, `cell="c3"`))
+
+	// Deleting a series makes room for a new one
+	vec.DeleteLabelValues("c2")
+	vec.WithLabelValues("c3").Set(3)
+	metrics, _ = Resource.GetLocalMetrics()
+	assert.True(t, strings.Contains(metrics, `ricxapp_TestMetricVecLimit_CellLoad{cell="c3"} 3`))
+}
+
+func TestMetricVecIdleEviction(t *testing.T) {
+	opts := HistogramOpts{Name: "RanLatency", Help: "Latency per RAN node"}
+	vec := Metric.RegisterHistogramVec(opts, []string{"meid"}, "TestMetricVecIdleEviction", VecOpts{IdleTimeout: time.Hour})
+	vec.WithLabelValues("gnb1").Observe(0.1)
+	vec.WithLabelValues("gnb2").Observe(0.2)
+
+	assert.Equal(t, 0, vec.series.evict(time.Now()))
+	vec.series.last[seriesKey([]string{"gnb1"})] = time.Now().Add(-2 * time.Hour)
+	assert.Equal(t, 1, vec.series.evict(time.Now()))
+	assert.Equal(t, 1, vec.Series())
+
+	metrics, _ := Resource.GetLocalMetrics()
+	assert.False(t, strings.Contains(metrics, `ricxapp_TestMetricVecIdleEviction_RanLatency_count{meid="gnb1"}`))
+	assert.True(t, strings.Contains(metrics, `ricxapp_TestMetricVecIdleEviction_RanLatency_count{meid="gnb2"} 1`))
+
+	// Evicted by the background sweep
+	svec := Metric.RegisterSummaryVec(SummaryOpts{Name: "RanDelay", Help: "Delay per RAN node"}, []string{"meid"}, "TestMetricVecIdleEviction", VecOpts{IdleTimeout: 20 * time.Millisecond})
+	svec.WithLabelValues("gnb1").Observe(1)
+	assert.Equal(t, 1, svec.Series())
+	assert.Eventually(t, func() bool { return svec.Series() == 0 }, 3*time.Second, 10*time.Millisecond)
+}
+
+func TestMetricVecIdleEvictionFixed(t *testing.T) {
+	opts := CounterOpts{Name: "RanErrors", Help: "Errors per RAN node"}
+	vec := Metric.RegisterCounterVec(opts, []string{"meid"}, "TestMetricVecIdleEvictionFixed", VecOpts{IdleTimeout: time.Hour})
+	fixed := Metric.RegisterLabeledCounter(opts, []string{"meid"}, []string{"gnb1"}, "TestMetricVecIdleEvictionFixed")
+	vec.WithLabelValues("gnb1").Inc()
+	vec.WithLabelValues("gnb2").Inc()
+
+	for _, v := range []string{"gnb1", "gnb2"} {
+		vec.series.last[seriesKey([]string{v})] = time.Now().Add(-2 * time.Hour)
+	}
+	assert.Equal(t, 1, vec.series.evict(time.Now()))
+	assert.Equal(t, 1, vec.Series())
+
+	// The cached handle is still exported
+	fixed.Inc()
+	metrics, _ := Resource.GetLocalMetrics()
+	assert.True(t, strings.Contains(metrics, `ricxapp_TestMetricVecIdleEvictionFixed_RanErrors{meid="gnb1"} 2`))
+	assert.False(t, strings.Contains(metrics, `ricxapp_TestMetricVecIdleEvictionFixed_RanErrors{meid="gnb2"}`))
+}
@@ -24,106 +24,13 @@ package xapp
 */
 import "C"
 
+import "gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/descriptor"
+
 //-----------------------------------------------------------------------------
 //
 //-----------------------------------------------------------------------------
-var RICMessageTypes = map[string]int{
-	"RIC_HEALTH_CHECK_REQ":                C.RIC_HEALTH_CHECK_REQ,
-	"RIC_HEALTH_CHECK_RESP":               C.RIC_HEALTH_CHECK_RESP,
-	"RIC_SCTP_CONNECTION_FAILURE":         C.RIC_SCTP_CONNECTION_FAILURE,
-	"RIC_SCTP_CLEAR_ALL":                  C.RIC_SCTP_CLEAR_ALL,
-	"E2_TERM_INIT":                        C.E2_TERM_INIT,
-	"E2_TERM_KEEP_ALIVE_REQ":              C.E2_TERM_KEEP_ALIVE_REQ,
-	"E2_TERM_KEEP_ALIVE_RESP":             C.E2_TERM_KEEP_ALIVE_RESP,
-	"RAN_CONNECTED":                       C.RAN_CONNECTED,
-	"RAN_RESTARTED":                       C.RAN_RESTARTED,
-	"RAN_RECONFIGURED":                    C.RAN_RECONFIGURED,
-	"RIC_SUB_REQ":                         C.RIC_SUB_REQ,
-	"RIC_SUB_RESP":                        C.RIC_SUB_RESP,
-	"RIC_SUB_FAILURE":                     C.RIC_SUB_FAILURE,
-	"RIC_SUB_DEL_REQ":                     C.RIC_SUB_DEL_REQ,
-	"RIC_SUB_DEL_RESP":                    C.RIC_SUB_DEL_RESP,
-	"RIC_SUB_DEL_FAILURE":                 C.RIC_SUB_DEL_FAILURE,
-	"RIC_SUB_DEL_REQUIRED":                C.RIC_SUB_DEL_REQUIRED,
-	"RIC_SERVICE_UPDATE":                  C.RIC_SERVICE_UPDATE,
-	"RIC_SERVICE_UPDATE_ACK":              C.RIC_SERVICE_UPDATE_ACK,
-	"RIC_SERVICE_UPDATE_FAILURE":          C.RIC_SERVICE_UPDATE_FAILURE,
-	"RIC_CONTROL_REQ":                     C.RIC_CONTROL_REQ,
-	"RIC_CONTROL_ACK":                     C.RIC_CONTROL_ACK,
-	"RIC_CONTROL_FAILURE":                 C.RIC_CONTROL_FAILURE,
-	"RIC_INDICATION":                      C.RIC_INDICATION,
-	"RIC_SERVICE_QUERY":                   C.RIC_SERVICE_QUERY,
-	"RIC_X2_SETUP_REQ":                    C.RIC_X2_SETUP_REQ,
-	"RIC_X2_SETUP_RESP":                   C.RIC_X2_SETUP_RESP,
-	"RIC_X2_SETUP_FAILURE":                C.RIC_X2_SETUP_FAILURE,
-	"RIC_X2_RESET":                        C.RIC_X2_RESET,
-	"RIC_X2_RESET_RESP":                   C.RIC_X2_RESET_RESP,
-	"RIC_ENDC_X2_SETUP_REQ":               C.RIC_ENDC_X2_SETUP_REQ,
-	"RIC_ENDC_X2_SETUP_RESP":              C.RIC_ENDC_X2_SETUP_RESP,
-	"RIC_ENDC_X2_SETUP_FAILURE":           C.RIC_ENDC_X2_SETUP_FAILURE,
-	"RIC_ENDC_CONF_UPDATE":                C.RIC_ENDC_CONF_UPDATE,
-	"RIC_ENDC_CONF_UPDATE_ACK":            C.RIC_ENDC_CONF_UPDATE_ACK,
-	"RIC_ENDC_CONF_UPDATE_FAILURE":        C.RIC_ENDC_CONF_UPDATE_FAILURE,
-	"RIC_RES_STATUS_REQ":                  C.RIC_RES_STATUS_REQ,
-	"RIC_RES_STATUS_RESP":                 C.RIC_RES_STATUS_RESP,
-	"RIC_RES_STATUS_FAILURE":              C.RIC_RES_STATUS_FAILURE,
-	"RIC_ENB_CONF_UPDATE":                 C.RIC_ENB_CONF_UPDATE,
-	"RIC_ENB_CONF_UPDATE_ACK":             C.RIC_ENB_CONF_UPDATE_ACK,
-	"RIC_ENB_CONF_UPDATE_FAILURE":         C.RIC_ENB_CONF_UPDATE_FAILURE,
-	"RIC_ENB_LOAD_INFORMATION":            C.RIC_ENB_LOAD_INFORMATION,
-	"RIC_GNB_STATUS_INDICATION":           C.RIC_GNB_STATUS_INDICATION,
-	"RIC_RESOURCE_STATUS_UPDATE":          C.RIC_RESOURCE_STATUS_UPDATE,
-	"RIC_ERROR_INDICATION":                C.RIC_ERROR_INDICATION,
-	"RIC_SGNB_ADDITION_REQ":               C.RIC_SGNB_ADDITION_REQ,
-	"RIC_SGNB_ADDITION_ACK":               C.RIC_SGNB_ADDITION_ACK,
-	"RIC_SGNB_ADDITION_REJECT":            C.RIC_SGNB_ADDITION_REJECT,
-	"RIC_SGNB_MOD_REQUEST":                C.RIC_SGNB_MOD_REQUEST,
-	"RIC_SGNB_MOD_REQUEST_ACK":            C.RIC_SGNB_MOD_REQUEST_ACK,
-	"RIC_SGNB_MOD_REQUEST_REJECT":         C.RIC_SGNB_MOD_REQUEST_REJ,
-	"RIC_SGNB_MOD_REQUIRED":               C.RIC_SGNB_MOD_REQUIRED,
-	"RIC_SGNB_MOD_CONFIRM":                C.RIC_SGNB_MOD_CONFIRM,
-	"RIC_SGNB_MOD_REFUSE":                 C.RIC_SGNB_MOD_REFUSE,
-	"RIC_SGNB_RELEASE_REQUEST":            C.RIC_SGNB_RELEASE_REQUEST,
-	"RIC_SGNB_RELEASE_CONFIRM":            C.RIC_SGNB_RELEASE_CONFIRM,
-	"RIC_SGNB_RELEASE_REQUIRED":           C.RIC_SGNB_RELEASE_REQUIRED,
-	"RIC_SGNB_RELEASE_REQUEST_ACK":        C.RIC_SGNB_RELEASE_REQUEST_ACK,
-	"RIC_SECONDARY_RAT_DATA_USAGE_REPORT": C.RIC_SECONDARY_RAT_DATA_USAGE_REPORT,
-	"RIC_SN_STATUS_TRANSFER":              C.RIC_SN_STATUS_TRANSFER,
-	"RIC_SGNB_RECONF_COMPLETE":            C.RIC_SGNB_RECONF_COMPLETE,
-	"RIC_RRC_TRANSFER":                    C.RIC_RRC_TRANSFER,
-	"RIC_UE_CONTEXT_RELEASE":              C.RIC_UE_CONTEXT_RELEASE,
-	"DC_ADM_INT_CONTROL":                  C.DC_ADM_INT_CONTROL,
-	"DC_ADM_INT_CONTROL_ACK":              C.DC_ADM_INT_CONTROL_ACK,
-	"DC_ADM_GET_POLICY":                   C.DC_ADM_GET_POLICY,
-	"DC_ADM_GET_POLICY_ACK":               C.DC_ADM_GET_POLICY_ACK,
-	"A1_POLICY_REQ":                       C.A1_POLICY_REQ,
-	"A1_POLICY_RESP":                      C.A1_POLICY_RESP,
-	"A1_POLICY_QUERY":                     C.A1_POLICY_QUERY,
-	"RIC_X2_SETUP":                        C.RIC_X2_SETUP,
-	"RIC_X2_RESPONSE":                     C.RIC_X2_RESPONSE,
-	"RIC_X2_RESOURCE_STATUS_REQUEST":      C.RIC_X2_RESOURCE_STATUS_REQUEST,
-	"RIC_X2_RESOURCE_STATUS_RESPONSE":     C.RIC_X2_RESOURCE_STATUS_RESPONSE,
-	"RIC_X2_LOAD_INFORMATION":             C.RIC_X2_LOAD_INFORMATION,
-	"RIC_E2_TERMINATION_HC_REQUEST":       C.RIC_E2_TERMINATION_HC_REQUEST,
-	"RIC_E2_TERMINATION_HC_RESPONSE":      C.RIC_E2_TERMINATION_HC_RESPONSE,
-	"RIC_E2_MANAGER_HC_REQUEST":           C.RIC_E2_MANAGER_HC_REQUEST,
-	"RIC_E2_MANAGER_HC_RESPONSE":          C.RIC_E2_MANAGER_HC_RESPONSE,
-	"RIC_CONTROL_XAPP_CONFIG_REQUEST":     C.RIC_CONTROL_XAPP_CONFIG_REQUEST,
-	"RIC_CONTROL_XAPP_CONFIG_RESPONSE":    C.RIC_CONTROL_XAPP_CONFIG_RESPONSE,
-	"RMRRM_TABLE_DATA":                    C.RMRRM_TABLE_DATA,
-	"RMRRM_REQ_TABLE":                     C.RMRRM_REQ_TABLE,
-	"RMRRM_TABLE_STATE":                   C.RMRRM_TABLE_STATE,
-	"RIC_E2_SETUP_REQ":                    C.RIC_E2_SETUP_REQ,
-	"RIC_E2_SETUP_RESP":                   C.RIC_E2_SETUP_RESP,
-	"RIC_E2_SETUP_FAILURE":                C.RIC_E2_SETUP_FAILURE,
-	"TS_UE_LIST":                          C.TS_UE_LIST,
-	"TS_QOE_PRED_REQ":                     C.TS_QOE_PRED_REQ,
-	"TS_QOE_PREDICTION":                   C.TS_QOE_PREDICTION,
-	"MC_REPORT":                           C.MC_REPORT,
-	"DCAPTERM_RTPM_RMR_MSGTYPE":           C.DCAPTERM_RTPM_RMR_MSGTYPE,
-	"DCAPTERM_GEO_RMR_MSGTYPE":            C.DCAPTERM_GEO_RMR_MSGTYPE,
-	"RIC_E2_RAN_ERROR_INDICATION":         C.RIC_E2_RAN_ERROR_INDICATION,
-}
+// RICMessageTypes are the message types known by RMR, with the messaging.mtypes of the config added
+var RICMessageTypes = descriptor.MessageTypes()
 
 //-----------------------------------------------------------------------------
 //
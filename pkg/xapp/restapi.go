@@ -42,6 +42,9 @@ const (
 	AliveURL     = "/ric/v1/health/alive"
 	ConfigURL    = "/ric/v1/cm/{name}"
 	AppConfigURL = "/ric/v1/config"
+	LoggerURL    = "/ric/v1/logger"
+
+	SubscriptionStatusURL = "/ric/v1/xapp/subscriptions"
 )
 
 var (
@@ -66,13 +69,22 @@ func NewRouter() *Router {
 	r.InjectRoute(AliveURL, aliveHandler, "GET")
 	r.InjectRoute(ConfigURL, configHandler, "POST")
 	r.InjectRoute(AppConfigURL, appconfigHandler, "GET")
+	r.InjectRoute(ConfigHistoryURL, configHistoryHandler, "GET")
+	r.InjectRoute(ConfigHistoryDiffURL, configDiffHandler, "GET")
+	r.InjectRoute(ConfigHistoryVersionURL, configVersionHandler, "GET")
+	r.InjectRoute(ConfigHistoryRollbackURL, configRollbackHandler, "POST")
+	r.InjectRoute(ConfigKeyURL, configKeyGetHandler, "GET")
+	r.InjectRoute(ConfigKeyURL, configKeyPutHandler, "PUT")
+	r.InjectRoute(LoggerURL, loggerGetHandler, "GET")
+	r.InjectRoute(LoggerURL, loggerPutHandler, "PUT")
 
 	return r
 }
 
 func (r *Router) serviceChecker(inner http.HandlerFunc) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		Logger.Debug("restapi: method=%s url=%s", req.Method, req.URL.RequestURI())
+		req = withRequestLogger(w, req)
+		RequestLogger(req).Debug("restapi: method=%s url=%s", req.Method, req.URL.RequestURI())
 		if req.URL.RequestURI() == AliveURL || r.CheckStatus() {
 			inner.ServeHTTP(w, req)
 		} else {
@@ -116,19 +128,25 @@ func (r *Router) GetSymptomDataParams(w http.ResponseWriter, req *http.Request)
 
 	for p := range queryParams {
 		if p == "timeout" {
-			fmt.Sscanf(p, "%d", &params.Timeout)
+			fmt.Sscanf(queryParams.Get(p), "%d", &params.Timeout)
 		}
 		if p == "fromtime" {
-			fmt.Sscanf(p, "%d", &params.FromTime)
+			fmt.Sscanf(queryParams.Get(p), "%d", &params.FromTime)
 		}
 		if p == "totime" {
-			fmt.Sscanf(p, "%d", &params.ToTime)
+			fmt.Sscanf(queryParams.Get(p), "%d", &params.ToTime)
 		}
 	}
 	return params
 }
 
 func (r *Router) CollectDefaultSymptomData(fileName string, data interface{}) string {
+	return r.CollectDefaultSymptomDataWithParams(fileName, data, SymptomDataParams{})
+}
+
+// CollectDefaultSymptomDataWithParams collects the default symptom data, the log
+// records are limited to the FromTime and ToTime of the params
+func (r *Router) CollectDefaultSymptomDataWithParams(fileName string, data interface{}, params SymptomDataParams) string {
 	baseDir := Config.GetString("controls.symptomdata.baseDir")
 	if baseDir == "" {
 		baseDir = "/tmp/xapp/"
@@ -193,6 +211,22 @@ func (r *Router) CollectDefaultSymptomData(fileName string, data interface{}) st
 		}
 	}
 
+	//
+	// Collect the status of the subscriptions made by this xApp
+	//
+	if Subscription != nil {
+		if b, err := json.MarshalIndent(Subscription.SubscriptionStatus(), "", "  "); err == nil {
+			Util.WriteToFile(baseDir+"subscriptions.json", string(b))
+		}
+	}
+
+	//
+	// Collect the recent log records kept in memory
+	//
+	if b, err := json.MarshalIndent(Logger.Records(symptomDataWindow(params)), "", "  "); err == nil {
+		Util.WriteToFile(baseDir+"logs.json", string(b))
+	}
+
 	//
 	// Put data that was provided as argument
 	//
@@ -238,7 +272,11 @@ func (r *Router) SendSymptomDataError(w http.ResponseWriter, req *http.Request,
 func (r *Router) GetLocalMetrics() (string, error) {
 	buf := &bytes.Buffer{}
 	enc := expfmt.NewEncoder(buf, expfmt.FmtText)
-	vals, err := prometheus.DefaultGatherer.Gather()
+	var gatherer prometheus.Gatherer = prometheus.DefaultGatherer
+	if Metric != nil {
+		gatherer = Metric.Gatherer()
+	}
+	vals, err := gatherer.Gather()
 	if err != nil {
 		return fmt.Sprintf("#metrics get error: %s\n", err.Error()), fmt.Errorf("Could get local metrics %w", err)
 	}
@@ -304,6 +342,10 @@ func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	}
 }
 
+func respondWithError(w http.ResponseWriter, code int, message string) {
+	respondWithJSON(w, code, map[string]string{"error": message})
+}
+
 func appconfigHandler(w http.ResponseWriter, r *http.Request) {
 
 	Logger.Info("Inside appconfigHandler")
@@ -338,7 +380,7 @@ func appconfigHandler(w http.ResponseWriter, r *http.Request) {
 	defer configFile.Close()
 
 	xappconfig.Metadata = &metadata
-	xappconfig.Config = string(body)
+	xappconfig.Config = liveConfigDocument(body)
 
 	appconfig = append(appconfig, &xappconfig)
 
@@ -66,6 +66,7 @@ import "C"
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"fmt"
 	"strings"
@@ -125,6 +126,7 @@ type RMRParams struct {
 	Callid     int
 	Timeout    int
 	status     int
+	ctx        context.Context
 }
 
 func (params *RMRParams) String() string {
@@ -155,12 +157,13 @@ func NewRMRClientWithParams(params *RMRClientParams) *RMRClient {
 	m := C.int(params.RmrData.MaxSize)
 	c := C.int(params.RmrData.ThreadType)
 	defer C.free(unsafe.Pointer(p))
+	log := Logger.Component("rmr")
 	ctx := C.rmr_init(p, m, c)
 	if ctx == nil {
-		Logger.Error("rmrClient: Initializing RMR context failed, bailing out!")
+		log.Error("rmrClient: Initializing RMR context failed, bailing out!")
 	}
 
-	Logger.Info("new rmrClient with parameters: %s", params.String())
+	log.Info("new rmrClient with parameters: %s", params.String())
 
 	if params.RmrData.LowLatency {
 		C.rmr_set_low_latency(ctx)
@@ -175,6 +178,7 @@ func NewRMRClientWithParams(params *RMRClientParams) *RMRClient {
 		statc:             Metric.RegisterCounterGroup(RMRCounterOpts, params.StatDesc),
 		statg:             Metric.RegisterGaugeGroup(RMRGaugeOpts, params.StatDesc),
 		maxRetryOnFailure: params.RmrData.MaxRetryOnFailure,
+		log:               log,
 	}
 }
 
@@ -208,11 +212,11 @@ func (m *RMRClient) Start(c MessageConsumer) {
 		m.ready = int(C.rmr_ready(m.context))
 		m.contextMux.Unlock()
 		if m.ready == 1 {
-			Logger.Info("rmrClient: RMR is ready after %d seconds waiting...", counter)
+			m.log.Info("rmrClient: RMR is ready after %d seconds waiting...", counter)
 			break
 		}
 		if counter%10 == 0 {
-			Logger.Info("rmrClient: Waiting for RMR to be ready ...")
+			m.log.Info("rmrClient: Waiting for RMR to be ready ...")
 		}
 		time.Sleep(1 * time.Second)
 		counter++
@@ -275,7 +279,7 @@ func (m *RMRClient) UpdateRmrStats() {
 
 func (m *RMRClient) parseMessage(rxBuffer *C.rmr_mbuf_t) {
 	if len(m.consumers) == 0 {
-		Logger.Info("rmrClient: No message handlers defined, message discarded!")
+		m.log.Info("rmrClient: No message handlers defined, message discarded!")
 		return
 	}
 
@@ -300,13 +304,22 @@ func (m *RMRClient) parseMessage(rxBuffer *C.rmr_mbuf_t) {
 		params.Src = strings.TrimRight(string(srcBuf[0:64]), "\000")
 	}
 
+	// Request-scoped logger for the consumer, see RMRParams.Context
+	fields := params.logFields()
+	params.ctx = NewLogContext(context.Background(), Logger.With(fields...))
+
+	// Managed subscriptions are re-created when the RAN connects or restarts
+	if Subscription != nil {
+		Subscription.handleRanEvent(params)
+	}
+
 	// Default case: a single consumer
 	if len(m.consumers) == 1 && m.consumers[0] != nil {
 		params.PayloadLen = int(rxBuffer.len)
 		params.Payload = (*[1 << 30]byte)(unsafe.Pointer(rxBuffer.payload))[:params.PayloadLen:params.PayloadLen]
 		err := m.consumers[0].Consume(params)
 		if err != nil {
-			Logger.Warn("rmrClient: Consumer returned error: %v", err)
+			m.log.With(fields...).Warn("rmrClient: Consumer returned error: %v", err)
 		}
 		return
 	}
@@ -333,7 +346,7 @@ func (m *RMRClient) Allocate(size int) *C.rmr_mbuf_t {
 	defer m.contextMux.Unlock()
 	outbuf := C.rmr_alloc_msg(m.context, C.int(size))
 	if outbuf == nil {
-		Logger.Error("rmrClient: Allocating message buffer failed!")
+		m.log.Error("rmrClient: Allocating message buffer failed!")
 	}
 	return outbuf
 }
@@ -343,7 +356,7 @@ func (m *RMRClient) ReAllocate(inbuf *C.rmr_mbuf_t, size int) *C.rmr_mbuf_t {
 	defer m.contextMux.Unlock()
 	outbuf := C.rmr_realloc_msg(inbuf, C.int(size))
 	if outbuf == nil {
-		Logger.Error("rmrClient: Allocating message buffer failed!")
+		m.log.Error("rmrClient: Allocating message buffer failed!")
 	}
 	return outbuf
 }
@@ -600,9 +613,9 @@ func (m *RMRClient) GetRicMessageName(id int) (s string) {
 
 func (m *RMRClient) LogMBufError(text string, mbuf *C.rmr_mbuf_t) int {
 	if mbuf != nil {
-		Logger.Debug(fmt.Sprintf("rmrClient: %s -> [tp=%v] %v - %s", text, mbuf.tp_state, mbuf.state, RMRErrors[int(mbuf.state)]))
+		m.log.Debug(fmt.Sprintf("rmrClient: %s -> [tp=%v] %v - %s", text, mbuf.tp_state, mbuf.state, RMRErrors[int(mbuf.state)]))
 		return int(mbuf.state)
 	}
-	Logger.Debug(fmt.Sprintf("rmrClient: %s -> mbuf nil", text))
+	m.log.Debug(fmt.Sprintf("rmrClient: %s -> mbuf nil", text))
 	return 0
 }
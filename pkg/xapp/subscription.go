@@ -20,19 +20,19 @@
 package xapp
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/go-openapi/loads"
 	httptransport "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
-	"github.com/spf13/viper"
 
 	apiclient "gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientapi"
 	apicommon "gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientapi/common"
@@ -47,17 +47,40 @@ import (
 type SubscriptionHandler func(interface{}) (*models.SubscriptionResponse, int)
 type SubscriptionQueryHandler func() (models.SubscriptionList, error)
 type SubscriptionDeleteHandler func(string) int
+type SubscriptionModifyHandler func(string, interface{}) (*models.SubscriptionResponse, int)
 type SubscriptionResponseCallback func(*apimodel.SubscriptionResponse)
 
+// SubscriptionResponseContextCallback gets the context of the notification, its logger
+// has the subscription ID, and the request ID of a received notification, as fields
+type SubscriptionResponseContextCallback func(context.Context, *apimodel.SubscriptionResponse)
+
 type Subscriber struct {
-	localAddr  string
-	localPort  int
-	remoteHost string
-	remoteUrl  string
-	remoteProt []string
-	timeout    time.Duration
-	clientUrl  string
-	clientCB   SubscriptionResponseCallback
+	localAddr    string
+	localPort    int
+	remoteHost   string
+	remoteUrl    string
+	remoteProt   []string
+	timeout      time.Duration
+	clientUrl    string
+	clientCB     SubscriptionResponseCallback
+	clientCtxCB  SubscriptionResponseContextCallback
+	handleMux    sync.Mutex
+	handles      map[string]*SubscriptionHandle
+	unbound      map[*SubscriptionHandle]bool
+	retryMux     sync.Mutex
+	retryPolicy  *SubscriptionRetryPolicy
+	retries      map[string]*subscriptionRetryState
+	retryStat    map[string]Counter
+	notifier     *NotificationDispatcher
+	notifierOnce sync.Once
+	statusMux    sync.Mutex
+	status       map[string]*SubscriptionStatus
+	pending      int
+	parked       []*apimodel.SubscriptionResponse
+	auth         *NotificationAuth
+	respStat     map[string]Counter
+	registryMux  sync.Mutex
+	registry     *SubscriptionRegistry
 }
 
 func NewSubscriber(host string, timo int) *Subscriber {
@@ -81,26 +104,94 @@ func NewSubscriber(host string, timo int) *Subscriber {
 		localAddr:  "0.0.0.0",
 		localPort:  8088,
 		clientUrl:  "/ric/v1/subscriptions/response",
+		handles:    make(map[string]*SubscriptionHandle),
+		unbound:    make(map[*SubscriptionHandle]bool),
+		retries:    make(map[string]*subscriptionRetryState),
+		status:     make(map[string]*SubscriptionStatus),
+		auth:       NewNotificationAuth(),
+		respStat:   Metric.RegisterCounterGroup(SubscriptionResponseCounterOpts, "Subscription"),
 	}
+	r.SetRetryPolicy(NewSubscriptionRetryPolicy())
 	Resource.InjectRoute(r.clientUrl, r.ResponseHandler, "POST")
+	Resource.InjectRoute(SubscriptionStatusURL, r.statusHandler, "GET")
 
 	return r
 }
 
 func (r *Subscriber) ResponseHandler(w http.ResponseWriter, req *http.Request) {
-	if req.Body != nil {
-		var resp apimodel.SubscriptionResponse
-		if err := json.NewDecoder(req.Body).Decode(&resp); err == nil {
-			if r.clientCB != nil {
-				r.clientCB(&resp)
-			}
+	if req.Body == nil {
+		r.rejectResponse(w, req, http.StatusBadRequest, "ResponsesMalformed", "empty body")
+		return
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		r.rejectResponse(w, req, http.StatusBadRequest, "ResponsesMalformed", err.Error())
+		return
+	}
+
+	if err := r.notificationAuth().Verify(req, body); err != nil {
+		r.rejectResponse(w, req, http.StatusUnauthorized, "ResponsesUnauthorized", err.Error())
+		return
+	}
+
+	var resp apimodel.SubscriptionResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		r.rejectResponse(w, req, http.StatusBadRequest, "ResponsesMalformed", err.Error())
+		return
+	}
+	if resp.SubscriptionID == nil {
+		r.rejectResponse(w, req, http.StatusBadRequest, "ResponsesMalformed", "no subscription ID")
+		return
+	}
+	req = req.WithContext(WithLogFields(req.Context(), LogFieldSubscriptionID, *resp.SubscriptionID))
+	if !r.isOutstanding(*resp.SubscriptionID) {
+		// The response may come before the ID is returned to the subscribe request
+		if !r.park(&resp) {
+			r.rejectResponse(w, req, http.StatusBadRequest, "ResponsesUnknown", fmt.Sprintf("unknown subscription '%s'", *resp.SubscriptionID))
+			return
 		}
-		req.Body.Close()
+		w.WriteHeader(http.StatusOK)
+		return
 	}
+
+	w.WriteHeader(http.StatusOK)
+	r.acceptResponse(req.Context(), &resp)
 }
 
-// Server interface: listen and receive subscription requests
-func (r *Subscriber) Listen(createSubscription SubscriptionHandler, getSubscription SubscriptionQueryHandler, delSubscription SubscriptionDeleteHandler) error {
+func (r *Subscriber) acceptResponse(ctx context.Context, resp *apimodel.SubscriptionResponse) {
+	r.respStat["ResponsesAccepted"].Inc()
+	for _, final := range r.applyRetryPolicy(resp) {
+		r.deliverResponse(ctx, final)
+	}
+}
+
+// responseContext returns a context whose logger has the subscription ID of the response as field
+func responseContext(resp *apimodel.SubscriptionResponse) context.Context {
+	return WithLogFields(context.Background(), LogFieldSubscriptionID, *resp.SubscriptionID)
+}
+
+func (r *Subscriber) rejectResponse(w http.ResponseWriter, req *http.Request, code int, counter, reason string) {
+	RequestLogger(req).Warn("Subscription response rejected: %s", reason)
+	r.respStat[counter].Inc()
+	http.Error(w, reason, code)
+}
+
+func (r *Subscriber) deliverResponse(ctx context.Context, resp *apimodel.SubscriptionResponse) {
+	LoggerFromContext(ctx).Debug("Subscription notification delivered: %d instances", len(resp.SubscriptionInstances))
+	r.recordResponse(resp)
+	r.dispatch(resp)
+	if r.clientCB != nil {
+		r.clientCB(resp)
+	}
+	if r.clientCtxCB != nil {
+		r.clientCtxCB(ctx, resp)
+	}
+}
+
+// Server interface: listen and receive subscription requests. The modify handler is optional,
+// modification requests are answered with 501 Not Implemented without it.
+func (r *Subscriber) Listen(createSubscription SubscriptionHandler, getSubscription SubscriptionQueryHandler, delSubscription SubscriptionDeleteHandler, modSubscription ...SubscriptionModifyHandler) error {
 	swaggerSpec, err := loads.Embedded(restapi.SwaggerJSON, restapi.FlatSwaggerJSON)
 	if err != nil {
 		return err
@@ -112,7 +203,8 @@ func (r *Subscriber) Listen(createSubscription SubscriptionHandler, getSubscript
 	api.CommonGetAllSubscriptionsHandler = common.GetAllSubscriptionsHandlerFunc(
 		func(p common.GetAllSubscriptionsParams) middleware.Responder {
 			if resp, err := getSubscription(); err == nil {
-				return common.NewGetAllSubscriptionsOK().WithPayload(resp)
+				page, total := newSubscriptionQueryFilter(p).Apply(resp)
+				return common.NewGetAllSubscriptionsOK().WithPayload(page).WithXTotalCount(total)
 			}
 			return common.NewGetAllSubscriptionsInternalServerError()
 		})
@@ -149,6 +241,27 @@ func (r *Subscriber) Listen(createSubscription SubscriptionHandler, getSubscript
 			return common.NewUnsubscribeNoContent()
 		})
 
+	// Subscription: Modify
+	if len(modSubscription) > 0 && modSubscription[0] != nil {
+		modifySubscription := modSubscription[0]
+		api.CommonModifySubscriptionHandler = common.ModifySubscriptionHandlerFunc(
+			func(p common.ModifySubscriptionParams) middleware.Responder {
+				resp, retCode := modifySubscription(p.SubscriptionID, p.SubscriptionParams)
+				switch retCode {
+				case common.ModifySubscriptionOKCode:
+					return common.NewModifySubscriptionOK().WithPayload(resp)
+				case common.ModifySubscriptionBadRequestCode:
+					return common.NewModifySubscriptionBadRequest()
+				case common.ModifySubscriptionNotFoundCode:
+					return common.NewModifySubscriptionNotFound()
+				case common.ModifySubscriptionServiceUnavailableCode:
+					return common.NewModifySubscriptionServiceUnavailable()
+				default:
+					return common.NewModifySubscriptionInternalServerError()
+				}
+			})
+	}
+
 	server := restapi.NewServer(api)
 	defer server.Shutdown()
 	server.Host = r.localAddr
@@ -161,42 +274,23 @@ func (r *Subscriber) Listen(createSubscription SubscriptionHandler, getSubscript
 	return nil
 }
 
-// Server interface: send notification to client
+// Server interface: send notification to client. The notification is delivered asynchronously,
+// an error is returned only if it could not be queued.
 func (r *Subscriber) Notify(resp *models.SubscriptionResponse, ep models.SubscriptionParamsClientEndpoint) (err error) {
-	respData, err := json.Marshal(resp)
-	if err != nil {
-		Logger.Error("json.Marshal failed: %v", err)
-		return err
-	}
-
-	clientUrl := fmt.Sprintf("http://%s:%d%s", ep.Host, *ep.HTTPPort, r.clientUrl)
-
-	retries := viper.GetInt("subscription.retryCount")
-	if retries == 0 {
-		retries = 10
-	}
-
-	delay := viper.GetInt("subscription.retryDelay")
-	if delay == 0 {
-		delay = 5
-	}
-
-	for i := 0; i < retries; i++ {
-		r, err := http.Post(clientUrl, "application/json", bytes.NewBuffer(respData))
-		if err == nil && (r != nil && r.StatusCode == http.StatusOK) {
-			break
-		}
+	return r.NotifyWithContext(context.Background(), resp, ep)
+}
 
-		if err != nil {
-			Logger.Error("%v", err)
-		}
-		if r != nil && r.StatusCode != http.StatusOK {
-			Logger.Error("clientUrl=%s statusCode=%d", clientUrl, r.StatusCode)
-		}
-		time.Sleep(time.Duration(delay) * time.Second)
-	}
+// Server interface: send notification to client, delivery is abandoned when ctx is done
+func (r *Subscriber) NotifyWithContext(ctx context.Context, resp *models.SubscriptionResponse, ep models.SubscriptionParamsClientEndpoint) error {
+	return r.Notifier().Dispatch(ctx, resp, ep)
+}
 
-	return err
+// Server interface: returns the dispatcher used by Notify, e.g. for setting the status callback
+func (r *Subscriber) Notifier() *NotificationDispatcher {
+	r.notifierOnce.Do(func() {
+		r.notifier = NewNotificationDispatcher(context.Background(), r.clientUrl)
+	})
+	return r.notifier
 }
 
 // Subscription interface for xApp: Response callback
@@ -204,8 +298,30 @@ func (r *Subscriber) SetResponseCB(c SubscriptionResponseCallback) {
 	r.clientCB = c
 }
 
+// Subscription interface for xApp: Response callback with the context of the notification
+func (r *Subscriber) SetResponseContextCB(c SubscriptionResponseContextCallback) {
+	r.clientCtxCB = c
+}
+
 // Subscription interface for xApp
 func (r *Subscriber) Subscribe(p *apimodel.SubscriptionParams) (*apimodel.SubscriptionResponse, error) {
+	r.beginRequest()
+	defer r.endRequest()
+
+	resp, err := r.subscribe(p)
+	if err != nil {
+		return resp, err
+	}
+	if resp != nil && resp.SubscriptionID != nil {
+		r.recordRequest(*resp.SubscriptionID, p, false)
+		r.trackRetries(*resp.SubscriptionID, p)
+	}
+	return resp, err
+}
+
+// subscribe sends the subscribe request without tracking it, used as such for the
+// retries of an already tracked subscription
+func (r *Subscriber) subscribe(p *apimodel.SubscriptionParams) (*apimodel.SubscriptionResponse, error) {
 	params := apicommon.NewSubscribeParamsWithTimeout(r.timeout).WithSubscriptionParams(p)
 	result, err := r.CreateTransport().Common.Subscribe(params)
 	if err != nil {
@@ -214,35 +330,144 @@ func (r *Subscriber) Subscribe(p *apimodel.SubscriptionParams) (*apimodel.Subscr
 	return result.Payload, err
 }
 
+// Subscription interface for xApp: PUT, replaces the subscription details of an existing subscription
+func (r *Subscriber) Modify(subId string, p *apimodel.SubscriptionParams) (*apimodel.SubscriptionResponse, error) {
+	params := apicommon.NewModifySubscriptionParamsWithTimeout(r.timeout).WithSubscriptionID(subId).WithSubscriptionParams(p)
+	result, err := r.CreateTransport().Common.ModifySubscription(params)
+	if err != nil {
+		r.recordError(subId, err)
+		return &apimodel.SubscriptionResponse{}, err
+	}
+	r.recordRequest(subId, p, true)
+
+	// Retries are done with the modified details from now on
+	r.untrackRetries(subId)
+	r.trackRetries(subId, p)
+	return result.Payload, err
+}
+
+// Subscription interface for xApp: returns a handle for waiting the notifications of this subscription
+func (r *Subscriber) SubscribeWithHandle(p *apimodel.SubscriptionParams) (*SubscriptionHandle, error) {
+	h := newSubscriptionHandle(r, p)
+
+	// Notifications may arrive before the subscription ID is known
+	r.handleMux.Lock()
+	r.unbound[h] = true
+	r.handleMux.Unlock()
+
+	resp, err := r.Subscribe(p)
+	if err != nil || resp.SubscriptionID == nil {
+		r.removeHandle(h)
+		if err == nil {
+			err = fmt.Errorf("no subscription ID in response")
+		}
+		return nil, err
+	}
+
+	r.handleMux.Lock()
+	delete(r.unbound, h)
+	h.bind(*resp.SubscriptionID)
+	r.handles[*resp.SubscriptionID] = h
+	r.handleMux.Unlock()
+
+	h.flush()
+	return h, nil
+}
+
+func (r *Subscriber) removeHandle(h *SubscriptionHandle) {
+	r.handleMux.Lock()
+	defer r.handleMux.Unlock()
+
+	delete(r.unbound, h)
+	if id := h.ID(); id != "" && r.handles[id] == h {
+		delete(r.handles, id)
+	}
+}
+
+func (r *Subscriber) dispatch(resp *apimodel.SubscriptionResponse) {
+	r.handleMux.Lock()
+	var h *SubscriptionHandle
+	if resp.SubscriptionID != nil {
+		h = r.handles[*resp.SubscriptionID]
+	}
+	if h != nil {
+		h.enqueue(resp)
+	} else {
+		for u := range r.unbound {
+			u.buffer(resp)
+		}
+	}
+	r.handleMux.Unlock()
+
+	if h != nil {
+		h.flush()
+	}
+}
+
 // Subscription interface for xApp: DELETE
 func (r *Subscriber) Unsubscribe(subId string) error {
+	r.untrackRetries(subId)
 	params := apicommon.NewUnsubscribeParamsWithTimeout(r.timeout).WithSubscriptionID(subId)
 	_, err := r.CreateTransport().Common.Unsubscribe(params)
+	if err != nil {
+		r.recordError(subId, err)
+		return err
+	}
 
-	return err
+	r.forget(subId)
+	return nil
 }
 
 // Subscription interface for xApp: QUERY
 func (r *Subscriber) QuerySubscriptions() (models.SubscriptionList, error) {
-	resp, err := http.Get(fmt.Sprintf("http://%s/%s/subscriptions", r.remoteHost, r.remoteUrl))
-	if err != nil {
-		return models.SubscriptionList{}, err
-	}
+	subscriptions, _, err := r.QuerySubscriptionsWithFilter(SubscriptionQueryFilter{})
+	return subscriptions, err
+}
 
-	defer resp.Body.Close()
+// Subscription interface for xApp: GET with filters and pagination. Returns the requested page
+// and the total number of matching subscriptions.
+func (r *Subscriber) QuerySubscriptionsWithFilter(f SubscriptionQueryFilter) (models.SubscriptionList, int64, error) {
+	params := apicommon.NewGetAllSubscriptionsParamsWithTimeout(r.timeout)
+	if f.Meid != "" {
+		params.SetMeid(&f.Meid)
+	}
+	if f.RANFunctionID != nil {
+		params.SetRanFunctionID(f.RANFunctionID)
+	}
+	if f.ClientEndpoint != "" {
+		params.SetClientEndpoint(&f.ClientEndpoint)
+	}
+	if f.SubscriptionIDPrefix != "" {
+		params.SetSubscriptionIDPrefix(&f.SubscriptionIDPrefix)
+	}
+	if f.Offset > 0 {
+		params.SetOffset(&f.Offset)
+	}
+	if f.Limit > 0 {
+		params.SetLimit(&f.Limit)
+	}
 
-	contents, err := ioutil.ReadAll(resp.Body)
+	result, err := r.CreateTransport().Common.GetAllSubscriptions(params)
 	if err != nil {
-		return models.SubscriptionList{}, err
+		return models.SubscriptionList{}, 0, err
 	}
 
-	subscriptions := models.SubscriptionList{}
-	err = json.Unmarshal([]byte(string(contents)), &subscriptions)
+	// The client and server models are identical
+	data, err := json.Marshal(result.Payload)
 	if err != nil {
-		return models.SubscriptionList{}, err
+		return models.SubscriptionList{}, 0, err
+	}
+	subscriptions := models.SubscriptionList{}
+	if err := json.Unmarshal(data, &subscriptions); err != nil {
+		return models.SubscriptionList{}, 0, err
 	}
 
-	return subscriptions, nil
+	// Older Subscription Managers don't report the total count
+	total := result.XTotalCount
+	if total == 0 {
+		total = int64(len(subscriptions))
+	}
+	return subscriptions, total, nil
 }
 
 func (r *Subscriber) CreateTransport() *apiclient.RICSubscription {
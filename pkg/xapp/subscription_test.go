@@ -8,6 +8,7 @@ package xapp
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -17,6 +18,7 @@ import (
 	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientmodel"
 	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/models"
 	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/restapi/operations/common"
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/submgrfake"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -45,7 +47,7 @@ func TestSetup(t *testing.T) {
 	suite = t
 
 	// Start the server to simulate SubManager
-	go Subscription.Listen(subscriptionHandler, queryHandler, deleteHandler)
+	go Subscription.Listen(subscriptionHandler, queryHandler, deleteHandler, modifyHandler)
 	time.Sleep(time.Duration(2) * time.Second)
 }
 
@@ -94,6 +96,8 @@ func TestFailureNotificationHandling(t *testing.T) {
 	subscriptionParams := GetSubscriptionparams()
 	subscriptionParams.SubscriptionID = "send_failure_notification"
 
+	// The callback expects this failure only, later notifications must not reach it
+	defer Subscription.SetResponseCB(nil)
 	Subscription.SetResponseCB(func(resp *clientmodel.SubscriptionResponse) {
 		assert.Equal(t, len(resp.SubscriptionInstances), 1)
 		assert.Equal(t, *resp.SubscriptionInstances[0].XappEventInstanceID, int64(11))
@@ -108,6 +112,116 @@ func TestFailureNotificationHandling(t *testing.T) {
 	<-time.After(1 * time.Second)
 }
 
+func TestSubscriptionContextCallback(t *testing.T) {
+	subscriptionParams := GetSubscriptionparams()
+	subscriptionParams.SubscriptionID = "myxapp"
+
+	ids := make(chan interface{}, 1)
+	defer Subscription.SetResponseContextCB(nil)
+	Subscription.SetResponseContextCB(func(ctx context.Context, resp *clientmodel.SubscriptionResponse) {
+		for _, f := range LoggerFromContext(ctx).fields {
+			if f.key == LogFieldSubscriptionID {
+				select {
+				case ids <- f.value:
+				default:
+				}
+			}
+		}
+	})
+
+	resp, err := Subscription.Subscribe(subscriptionParams)
+	assert.Nil(t, err)
+	select {
+	case id := <-ids:
+		assert.Equal(t, *resp.SubscriptionID, id)
+	case <-time.After(time.Second):
+		t.Error("no notification with the subscription ID")
+	}
+}
+
+func TestSubscriptionHandleWait(t *testing.T) {
+	h, err := Subscription.SubscribeWithHandle(GetSubscriptionparams())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, h.ID(), "gnb123456-localhost")
+
+	notified := make(chan *clientmodel.SubscriptionResponse, 1)
+	h.OnResponse(func(resp *clientmodel.SubscriptionResponse) {
+		notified <- resp
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := h.Wait(ctx)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, *resp.SubscriptionID, "gnb123456-localhost")
+	assert.Equal(t, len(resp.SubscriptionInstances), 1)
+	assert.Equal(t, *resp.SubscriptionInstances[0].E2EventInstanceID, int64(22))
+
+	select {
+	case resp = <-notified:
+		assert.Equal(t, *resp.SubscriptionID, "gnb123456-localhost")
+	case <-time.After(1 * time.Second):
+		t.Error("OnResponse callback not called")
+	}
+	h.Close()
+}
+
+func TestSubscriptionHandleEarlyNotification(t *testing.T) {
+	fake := submgrfake.New()
+	assert.Nil(t, fake.Start(""))
+	defer fake.Stop()
+
+	// The Subscription Manager notifies before it responds to the request
+	defer func(host string, cb SubscriptionResponseCallback) {
+		Subscription.remoteHost = host
+		Subscription.SetResponseCB(cb)
+	}(Subscription.remoteHost, Subscription.clientCB)
+	Subscription.remoteHost = fake.Host()
+	Subscription.SetResponseCB(nil)
+	fake.ScriptSubscribe(submgrfake.Outcome{NotificationFirst: true})
+
+	h, err := Subscription.SubscribeWithHandle(GetSubscriptionparams())
+	assert.Equal(t, err, nil)
+	defer h.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	resp, err := h.Wait(ctx)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, *resp.SubscriptionID, h.ID())
+
+	sent := fake.Notifications()
+	assert.Equal(t, len(sent), 1)
+	assert.Equal(t, *resp.SubscriptionInstances[0].E2EventInstanceID, *sent[0].SubscriptionInstances[0].E2EventInstanceID)
+
+	_, err = newSubscriptionHandle(Subscription, nil).Wait(ctx)
+	assert.Equal(t, err, context.DeadlineExceeded)
+}
+
+func TestSubscriptionHandleOrder(t *testing.T) {
+	id := "order-id"
+	h := newSubscriptionHandle(Subscription, nil)
+
+	var received []int64
+	h.OnResponse(func(resp *clientmodel.SubscriptionResponse) {
+		received = append(received, *resp.SubscriptionInstances[0].E2EventInstanceID)
+	})
+	notification := func(e2InstanceId int64) *clientmodel.SubscriptionResponse {
+		return &clientmodel.SubscriptionResponse{
+			SubscriptionID:        &id,
+			SubscriptionInstances: []*clientmodel.SubscriptionInstance{{E2EventInstanceID: &e2InstanceId}},
+		}
+	}
+
+	// Buffered notifications are delivered before the ones received after binding
+	h.buffer(notification(1))
+	h.buffer(notification(2))
+	h.bind(id)
+	h.enqueue(notification(3))
+	h.flush()
+	assert.Equal(t, received, []int64{1, 2, 3})
+}
+
 func TestBadRequestSubscriptionHandling(t *testing.T) {
 	subscriptionParams := GetSubscriptionparams()
 	subscriptionParams.SubscriptionID = "send_400_bad_request_response"
@@ -152,6 +266,17 @@ func TestServiceUnavailableSubscriptionHandling(t *testing.T) {
 	fmt.Println("Error:", err)
 }
 
+func TestSubscriptionModifyHandling(t *testing.T) {
+	subscriptionParams := GetSubscriptionparams()
+
+	resp, err := Subscription.Modify("modify-me", subscriptionParams)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, *resp.SubscriptionID, "modify-me")
+
+	_, err = Subscription.Modify("unknown", subscriptionParams)
+	assert.NotEqual(t, err, nil)
+}
+
 func GetSubscriptionparams() *clientmodel.SubscriptionParams {
 	return &clientmodel.SubscriptionParams{
 		SubscriptionID: "",
@@ -214,8 +339,9 @@ func TestResponseHandler(t *testing.T) {
 	handler := http.HandlerFunc(Subscription.ResponseHandler)
 	handler.ServeHTTP(rr, req)
 
-	if status := rr.Code; status != http.StatusOK {
-		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	// Malformed payload is rejected
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
 	}
 	time.Sleep(time.Duration(2) * time.Second)
 }
@@ -334,6 +460,16 @@ func queryHandler() (models.SubscriptionList, error) {
 	return resp, nil
 }
 
+func modifyHandler(id string, params interface{}) (*models.SubscriptionResponse, int) {
+	p := params.(*models.SubscriptionParams)
+	assert.Equal(suite, meid, *p.Meid)
+
+	if id != "modify-me" {
+		return nil, common.ModifySubscriptionNotFoundCode
+	}
+	return &models.SubscriptionResponse{SubscriptionID: &id}, common.ModifySubscriptionOKCode
+}
+
 func deleteHandler(ep string) int {
 	assert.Equal(suite, subscriptionId, ep)
 	if subscriptionId == "send_201_successful_response" {
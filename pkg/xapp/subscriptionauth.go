@@ -0,0 +1,154 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/viper"
+
+	apimodel "gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientmodel"
+)
+
+// NotificationSignatureHeader carries "sha256=<hex HMAC-SHA256 of the body>"
+const NotificationSignatureHeader = "X-Ric-Signature"
+
+var SubscriptionResponseCounterOpts = []CounterOpts{
+	{Name: "ResponsesAccepted", Help: "The total number of subscription responses accepted"},
+	{Name: "ResponsesMalformed", Help: "The total number of subscription responses rejected due to malformed content"},
+	{Name: "ResponsesUnauthorized", Help: "The total number of subscription responses rejected due to failed authentication"},
+	{Name: "ResponsesUnknown", Help: "The total number of subscription responses rejected due to no matching subscription"},
+}
+
+// NotificationAuth authenticates the subscription responses sent by the Subscription Manager.
+// The body is signed with HMAC-SHA256 using a shared secret.
+type NotificationAuth struct {
+	Secret []byte
+}
+
+// NewNotificationAuth reads the settings from controls.subscription.notificationAuth,
+// returns nil if authentication is not enabled
+func NewNotificationAuth() *NotificationAuth {
+	secret := viper.GetString("controls.subscription.notificationAuth.secret")
+	if secret == "" {
+		return nil
+	}
+	return &NotificationAuth{Secret: []byte(secret)}
+}
+
+// Sign returns the value of the signature header for body, empty if no secret is set
+func (a *NotificationAuth) Sign(body []byte) string {
+	if a == nil || len(a.Secret) == 0 {
+		return ""
+	}
+	mac := hmac.New(sha256.New, a.Secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of the request
+func (a *NotificationAuth) Verify(req *http.Request, body []byte) error {
+	if a == nil || len(a.Secret) == 0 {
+		return nil
+	}
+
+	sig := req.Header.Get(NotificationSignatureHeader)
+	if sig == "" {
+		return fmt.Errorf("missing %s header", NotificationSignatureHeader)
+	}
+	if !hmac.Equal([]byte(sig), []byte(a.Sign(body))) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// SetNotificationAuth sets the authentication of subscription responses, nil disables
+func (r *Subscriber) SetNotificationAuth(a *NotificationAuth) {
+	r.statusMux.Lock()
+	defer r.statusMux.Unlock()
+	r.auth = a
+}
+
+func (r *Subscriber) notificationAuth() *NotificationAuth {
+	r.statusMux.Lock()
+	defer r.statusMux.Unlock()
+	return r.auth
+}
+
+// isOutstanding returns true if the response belongs to a subscription of this xApp
+func (r *Subscriber) isOutstanding(id string) bool {
+	r.statusMux.Lock()
+	defer r.statusMux.Unlock()
+
+	_, ok := r.status[id]
+	return ok
+}
+
+// park keeps a response of an unknown subscription while subscribe requests are in
+// flight, as its ID may be returned by one of them. Returns false if none is in flight.
+func (r *Subscriber) park(resp *apimodel.SubscriptionResponse) bool {
+	r.statusMux.Lock()
+	defer r.statusMux.Unlock()
+
+	if r.pending == 0 {
+		return false
+	}
+	r.parked = append(r.parked, resp)
+	return true
+}
+
+func (r *Subscriber) beginRequest() {
+	r.statusMux.Lock()
+	r.pending++
+	r.statusMux.Unlock()
+}
+
+// endRequest delivers the parked responses of the subscriptions now known. The rest
+// are dropped once no request is in flight.
+func (r *Subscriber) endRequest() {
+	var known, unknown []*apimodel.SubscriptionResponse
+
+	r.statusMux.Lock()
+	r.pending--
+	parked := r.parked
+	r.parked = nil
+	for _, resp := range parked {
+		if _, ok := r.status[*resp.SubscriptionID]; ok {
+			known = append(known, resp)
+		} else if r.pending > 0 {
+			r.parked = append(r.parked, resp)
+		} else {
+			unknown = append(unknown, resp)
+		}
+	}
+	r.statusMux.Unlock()
+
+	for _, resp := range unknown {
+		Logger.Warn("Subscription response dropped: unknown subscription '%s'", *resp.SubscriptionID)
+		r.respStat["ResponsesUnknown"].Inc()
+	}
+	for _, resp := range known {
+		r.acceptResponse(responseContext(resp), resp)
+	}
+}
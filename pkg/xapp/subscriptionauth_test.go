@@ -0,0 +1,108 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apimodel "gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientmodel"
+)
+
+func postResponse(r *Subscriber, payload string, sig string) int {
+	req, _ := http.NewRequest("POST", "/ric/v1/subscriptions/response", bytes.NewBufferString(payload))
+	if sig != "" {
+		req.Header.Set(NotificationSignatureHeader, sig)
+	}
+	rr := httptest.NewRecorder()
+	r.ResponseHandler(rr, req)
+	return rr.Code
+}
+
+func newAuthTestSubscriber() *Subscriber {
+	return &Subscriber{
+		status:   make(map[string]*SubscriptionStatus),
+		retries:  make(map[string]*subscriptionRetryState),
+		respStat: Metric.RegisterCounterGroup(SubscriptionResponseCounterOpts, "Subscription"),
+	}
+}
+
+func TestSubscriptionResponseValidation(t *testing.T) {
+	r := newAuthTestSubscriber()
+	r.recordRequest("auth-1", GetSubscriptionparams(), false)
+
+	var delivered []*apimodel.SubscriptionResponse
+	r.SetResponseCB(func(resp *apimodel.SubscriptionResponse) { delivered = append(delivered, resp) })
+
+	assert.Equal(t, http.StatusBadRequest, postResponse(r, `{"SubscriptionId":`, ""))
+	assert.Equal(t, http.StatusBadRequest, postResponse(r, `{"SubscriptionInstances":[]}`, ""))
+	assert.Equal(t, http.StatusBadRequest, postResponse(r, `{"SubscriptionId":"unknown","SubscriptionInstances":[]}`, ""))
+	assert.Equal(t, 0, len(delivered))
+
+	assert.Equal(t, http.StatusOK, postResponse(r, `{"SubscriptionId":"auth-1","SubscriptionInstances":[]}`, ""))
+	assert.Equal(t, 1, len(delivered))
+
+	// While a subscribe request is in flight, responses are delivered only if it returns their ID
+	r.beginRequest()
+	assert.Equal(t, http.StatusOK, postResponse(r, `{"SubscriptionId":"auth-2","SubscriptionInstances":[]}`, ""))
+	assert.Equal(t, http.StatusOK, postResponse(r, `{"SubscriptionId":"auth-3","SubscriptionInstances":[]}`, ""))
+	assert.Equal(t, 1, len(delivered))
+	r.recordRequest("auth-3", GetSubscriptionparams(), false)
+	r.endRequest()
+	assert.Equal(t, 2, len(delivered))
+	assert.Equal(t, "auth-3", *delivered[1].SubscriptionID)
+	assert.False(t, r.park(&apimodel.SubscriptionResponse{}))
+}
+
+func TestSubscriptionResponseSignature(t *testing.T) {
+	r := newAuthTestSubscriber()
+	r.recordRequest("auth-1", GetSubscriptionparams(), false)
+	auth := &NotificationAuth{Secret: []byte("secret")}
+	r.SetNotificationAuth(auth)
+
+	payload := `{"SubscriptionId":"auth-1","SubscriptionInstances":[]}`
+	assert.Equal(t, http.StatusUnauthorized, postResponse(r, payload, ""))
+	assert.Equal(t, http.StatusUnauthorized, postResponse(r, payload, (&NotificationAuth{Secret: []byte("other")}).Sign([]byte(payload))))
+	assert.Equal(t, http.StatusOK, postResponse(r, payload, auth.Sign([]byte(payload))))
+}
+
+func TestSubscriptionNotificationSigned(t *testing.T) {
+	auth := &NotificationAuth{Secret: []byte("secret")}
+	r := newAuthTestSubscriber()
+	r.SetNotificationAuth(auth)
+	r.recordRequest("notify-test", GetSubscriptionparams(), false)
+
+	server := httptest.NewServer(http.HandlerFunc(r.ResponseHandler))
+	defer server.Close()
+
+	d, status := newTestDispatcher(t)
+	d.auth = auth
+	defer d.Stop()
+
+	assert.Nil(t, d.Dispatch(context.Background(), testNotification(), testEndpoint(t, server.Listener.Addr().String())))
+	s := waitStatus(t, status)
+	assert.Nil(t, s.err)
+	assert.Equal(t, 1, s.attempts)
+}
@@ -0,0 +1,182 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"context"
+	"sync"
+
+	apimodel "gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientmodel"
+)
+
+// SubscriptionHandle tracks the notifications of a single subscription.
+// Notifications received before the subscription ID is known are buffered
+// and matched once Subscribe has returned. The notifications are queued under
+// the handle lock of the subscriber, so that they are delivered in the order received.
+type SubscriptionHandle struct {
+	mux        sync.Mutex
+	subscriber *Subscriber
+	id         string
+	expected   int
+	early      []*apimodel.SubscriptionResponse
+	queue      []*apimodel.SubscriptionResponse
+	delivering bool
+	instances  []*apimodel.SubscriptionInstance
+	callbacks  []SubscriptionResponseCallback
+	final      *apimodel.SubscriptionResponse
+	done       chan struct{}
+}
+
+func newSubscriptionHandle(s *Subscriber, p *apimodel.SubscriptionParams) *SubscriptionHandle {
+	expected := 1
+	if p != nil && len(p.SubscriptionDetails) > 0 {
+		expected = len(p.SubscriptionDetails)
+	}
+
+	return &SubscriptionHandle{
+		subscriber: s,
+		expected:   expected,
+		done:       make(chan struct{}),
+	}
+}
+
+// ID returns the subscription ID allocated by the Subscription Manager
+func (h *SubscriptionHandle) ID() string {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	return h.id
+}
+
+// OnResponse registers a callback for every notification of this subscription.
+// Notifications already received are replayed to the callback.
+func (h *SubscriptionHandle) OnResponse(cb SubscriptionResponseCallback) {
+	if cb == nil {
+		return
+	}
+
+	h.mux.Lock()
+	h.callbacks = append(h.callbacks, cb)
+	var replay *apimodel.SubscriptionResponse
+	if len(h.instances) > 0 {
+		replay = h.response()
+	}
+	h.mux.Unlock()
+
+	if replay != nil {
+		cb(replay)
+	}
+}
+
+// Wait blocks until all subscription instances have been reported or ctx is done
+func (h *SubscriptionHandle) Wait(ctx context.Context) (*apimodel.SubscriptionResponse, error) {
+	select {
+	case <-h.done:
+		h.mux.Lock()
+		defer h.mux.Unlock()
+		return h.final, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Unsubscribe deletes the subscription and releases the handle
+func (h *SubscriptionHandle) Unsubscribe() error {
+	h.Close()
+	return h.subscriber.Unsubscribe(h.ID())
+}
+
+// Close stops notification delivery to this handle
+func (h *SubscriptionHandle) Close() {
+	h.subscriber.removeHandle(h)
+}
+
+// buffer keeps a notification received before the subscription ID is known,
+// the handle lock of the subscriber must be held
+func (h *SubscriptionHandle) buffer(resp *apimodel.SubscriptionResponse) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	h.early = append(h.early, resp)
+}
+
+// bind sets the subscription ID and queues the buffered notifications matching it,
+// the handle lock of the subscriber must be held
+func (h *SubscriptionHandle) bind(id string) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	h.id = id
+	for _, resp := range h.early {
+		if resp.SubscriptionID != nil && *resp.SubscriptionID == id {
+			h.queue = append(h.queue, resp)
+		}
+	}
+	h.early = nil
+}
+
+// enqueue queues a notification for delivery, the handle lock of the subscriber must be held
+func (h *SubscriptionHandle) enqueue(resp *apimodel.SubscriptionResponse) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	h.queue = append(h.queue, resp)
+}
+
+// flush delivers the queued notifications in order. If another goroutine is
+// delivering, it takes care of the notifications queued meanwhile.
+func (h *SubscriptionHandle) flush() {
+	h.mux.Lock()
+	if h.delivering {
+		h.mux.Unlock()
+		return
+	}
+	h.delivering = true
+
+	for len(h.queue) > 0 {
+		resp := h.queue[0]
+		h.queue = h.queue[1:]
+
+		h.instances = append(h.instances, resp.SubscriptionInstances...)
+		if h.final == nil && len(h.instances) >= h.expected {
+			h.final = h.response()
+			close(h.done)
+		}
+		callbacks := make([]SubscriptionResponseCallback, len(h.callbacks))
+		copy(callbacks, h.callbacks)
+		h.mux.Unlock()
+
+		for _, cb := range callbacks {
+			cb(resp)
+		}
+		h.mux.Lock()
+	}
+	h.delivering = false
+	h.mux.Unlock()
+}
+
+// response aggregates the instances received so far, h.mux must be held
+func (h *SubscriptionHandle) response() *apimodel.SubscriptionResponse {
+	id := h.id
+	instances := make([]*apimodel.SubscriptionInstance, len(h.instances))
+	copy(instances, h.instances)
+
+	return &apimodel.SubscriptionResponse{
+		SubscriptionID:        &id,
+		SubscriptionInstances: instances,
+	}
+}
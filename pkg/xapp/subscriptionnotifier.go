@@ -0,0 +1,289 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/models"
+)
+
+var NotificationCounterOpts = []CounterOpts{
+	{Name: "NotificationsDelivered", Help: "The total number of subscription notifications delivered"},
+	{Name: "NotificationsFailed", Help: "The total number of subscription notifications failed after all attempts"},
+	{Name: "NotificationRetries", Help: "The total number of subscription notification retries"},
+	{Name: "NotificationsDropped", Help: "The total number of subscription notifications dropped due to full queue"},
+}
+
+// NotificationStatusCallback reports the final outcome of a notification, err is nil if delivered
+type NotificationStatusCallback func(resp *models.SubscriptionResponse, endpoint string, attempts int, err error)
+
+type notificationJob struct {
+	ctx  context.Context
+	resp *models.SubscriptionResponse
+	data []byte
+	url  string
+}
+
+// -----------------------------------------------------------------------------
+// NotificationDispatcher delivers subscription notifications asynchronously.
+// Each client endpoint has its own queue and worker, so an unreachable xApp
+// delays only its own notifications.
+// -----------------------------------------------------------------------------
+type NotificationDispatcher struct {
+	mux          sync.Mutex
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	client       *http.Client
+	path         string
+	queues       map[string]chan *notificationJob
+	statusCB     NotificationStatusCallback
+	auth         *NotificationAuth
+	stat         map[string]Counter
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	Jitter       float64 // Random +/- fraction of the delay
+	QueueSize    int
+	IdleTimeout  time.Duration // Idle worker of an endpoint is stopped after this
+}
+
+// NewNotificationDispatcher creates a dispatcher posting to path on the client endpoints.
+// The retry settings are read from subscription.retryCount, subscription.retryDelay (s),
+// subscription.maxRetryDelay (s) and subscription.queueSize. The notifications are
+// signed if controls.subscription.notificationAuth.secret is set.
+func NewNotificationDispatcher(ctx context.Context, path string) *NotificationDispatcher {
+	ctx, cancel := context.WithCancel(ctx)
+	d := &NotificationDispatcher{
+		ctx:          ctx,
+		cancel:       cancel,
+		client:       &http.Client{Timeout: 5 * time.Second},
+		path:         path,
+		auth:         NewNotificationAuth(),
+		queues:       make(map[string]chan *notificationJob),
+		stat:         Metric.RegisterCounterGroup(NotificationCounterOpts, "Subscription"),
+		MaxAttempts:  viper.GetInt("subscription.retryCount"),
+		InitialDelay: time.Duration(viper.GetInt("subscription.retryDelay")) * time.Second,
+		MaxDelay:     time.Duration(viper.GetInt("subscription.maxRetryDelay")) * time.Second,
+		Multiplier:   2,
+		Jitter:       0.2,
+		QueueSize:    viper.GetInt("subscription.queueSize"),
+		IdleTimeout:  time.Minute,
+	}
+	if d.MaxAttempts == 0 {
+		d.MaxAttempts = 10
+	}
+	if d.InitialDelay == 0 {
+		d.InitialDelay = 1 * time.Second
+	}
+	if d.MaxDelay == 0 {
+		d.MaxDelay = 60 * time.Second
+	}
+	if d.QueueSize == 0 {
+		d.QueueSize = 100
+	}
+	return d
+}
+
+// SetStatusCB sets the callback reporting the outcome of every notification
+func (d *NotificationDispatcher) SetStatusCB(cb NotificationStatusCallback) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.statusCB = cb
+}
+
+// Dispatch queues the notification for delivery, it returns an error only if
+// the notification could not be queued
+func (d *NotificationDispatcher) Dispatch(ctx context.Context, resp *models.SubscriptionResponse, ep models.SubscriptionParamsClientEndpoint) error {
+	if ep.HTTPPort == nil {
+		return fmt.Errorf("client endpoint '%s' has no http port", ep.Host)
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		Logger.Error("json.Marshal failed: %v", err)
+		return err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	endpoint := fmt.Sprintf("%s:%d", ep.Host, *ep.HTTPPort)
+	job := &notificationJob{ctx: ctx, resp: resp, data: data, url: fmt.Sprintf("http://%s%s", endpoint, d.path)}
+
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	if d.ctx.Err() != nil {
+		return fmt.Errorf("notification dispatcher stopped")
+	}
+
+	q, ok := d.queues[endpoint]
+	if !ok {
+		q = make(chan *notificationJob, d.QueueSize)
+		d.queues[endpoint] = q
+		d.wg.Add(1)
+		go d.worker(endpoint, q)
+	}
+
+	select {
+	case q <- job:
+		return nil
+	default:
+		d.stat["NotificationsDropped"].Inc()
+		return fmt.Errorf("notification queue of '%s' is full", endpoint)
+	}
+}
+
+// Stop cancels the pending notifications and waits for the workers to exit
+func (d *NotificationDispatcher) Stop() {
+	d.mux.Lock()
+	d.cancel()
+	d.mux.Unlock()
+	d.wg.Wait()
+}
+
+func (d *NotificationDispatcher) worker(endpoint string, q chan *notificationJob) {
+	defer d.wg.Done()
+
+	idle := time.NewTimer(d.IdleTimeout)
+	defer idle.Stop()
+
+	for {
+		select {
+		case job := <-q:
+			d.deliver(endpoint, job)
+			idle.Reset(d.IdleTimeout)
+		case <-idle.C:
+			// Jobs are queued under d.mux, so none can be lost here
+			d.mux.Lock()
+			if len(q) == 0 {
+				delete(d.queues, endpoint)
+				d.mux.Unlock()
+				return
+			}
+			d.mux.Unlock()
+			idle.Reset(d.IdleTimeout)
+		case <-d.ctx.Done():
+			for {
+				select {
+				case job := <-q:
+					d.report(job, endpoint, 0, d.ctx.Err())
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (d *NotificationDispatcher) deliver(endpoint string, job *notificationJob) {
+	ctx, cancel := context.WithCancel(job.ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-d.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var err error
+	for attempt := 1; attempt <= d.MaxAttempts; attempt++ {
+		if err = d.post(ctx, job); err == nil {
+			d.stat["NotificationsDelivered"].Inc()
+			d.report(job, endpoint, attempt, nil)
+			return
+		}
+		Logger.Error("Notification to %s failed, attempt %d: %v", job.url, attempt, err)
+
+		if attempt == d.MaxAttempts {
+			break
+		}
+		select {
+		case <-time.After(d.delay(attempt)):
+			d.stat["NotificationRetries"].Inc()
+		case <-ctx.Done():
+			d.stat["NotificationsFailed"].Inc()
+			d.report(job, endpoint, attempt, ctx.Err())
+			return
+		}
+	}
+
+	d.stat["NotificationsFailed"].Inc()
+	d.report(job, endpoint, d.MaxAttempts, err)
+}
+
+func (d *NotificationDispatcher) post(ctx context.Context, job *notificationJob) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.url, bytes.NewReader(job.data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if sig := d.auth.Sign(job.data); sig != "" {
+		req.Header.Set(NotificationSignatureHeader, sig)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("statusCode=%d", resp.StatusCode)
+	}
+	return nil
+}
+
+// delay returns the backoff before the next attempt, with random jitter
+func (d *NotificationDispatcher) delay(attempt int) time.Duration {
+	delay := float64(d.InitialDelay)
+	for i := 1; i < attempt; i++ {
+		delay *= d.Multiplier
+	}
+	if d.MaxDelay > 0 && delay > float64(d.MaxDelay) {
+		delay = float64(d.MaxDelay)
+	}
+	if d.Jitter > 0 {
+		delay += delay * d.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+func (d *NotificationDispatcher) report(job *notificationJob, endpoint string, attempts int, err error) {
+	d.mux.Lock()
+	cb := d.statusCB
+	d.mux.Unlock()
+
+	if cb != nil {
+		cb(job.resp, endpoint, attempts, err)
+	}
+}
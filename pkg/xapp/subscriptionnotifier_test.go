@@ -0,0 +1,142 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/models"
+)
+
+type notificationStatus struct {
+	endpoint string
+	attempts int
+	err      error
+}
+
+func newTestDispatcher(t *testing.T) (*NotificationDispatcher, chan notificationStatus) {
+	d := NewNotificationDispatcher(context.Background(), "/ric/v1/subscriptions/response")
+	d.MaxAttempts = 3
+	d.InitialDelay = 50 * time.Millisecond
+	d.MaxDelay = 100 * time.Millisecond
+
+	status := make(chan notificationStatus, 10)
+	d.SetStatusCB(func(resp *models.SubscriptionResponse, endpoint string, attempts int, err error) {
+		status <- notificationStatus{endpoint, attempts, err}
+	})
+	return d, status
+}
+
+func testEndpoint(t *testing.T, addr string) models.SubscriptionParamsClientEndpoint {
+	host, port, _ := net.SplitHostPort(addr)
+	p, _ := strconv.ParseInt(port, 10, 64)
+	return models.SubscriptionParamsClientEndpoint{Host: host, HTTPPort: &p}
+}
+
+func testNotification() *models.SubscriptionResponse {
+	id := "notify-test"
+	return &models.SubscriptionResponse{SubscriptionID: &id}
+}
+
+func waitStatus(t *testing.T, status chan notificationStatus) notificationStatus {
+	select {
+	case s := <-status:
+		return s
+	case <-time.After(3 * time.Second):
+		t.Fatal("notification status not reported")
+	}
+	return notificationStatus{}
+}
+
+func TestNotificationDispatcherRetriesUntilDelivered(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	d, status := newTestDispatcher(t)
+	defer d.Stop()
+
+	assert.Nil(t, d.Dispatch(context.Background(), testNotification(), testEndpoint(t, server.Listener.Addr().String())))
+	s := waitStatus(t, status)
+	assert.Nil(t, s.err)
+	assert.Equal(t, 3, s.attempts)
+}
+
+func TestNotificationDispatcherUnreachableEndpointDoesNotBlockOthers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// Nothing listens on a closed listener's port
+	l, _ := net.Listen("tcp", "127.0.0.1:0")
+	unreachable := l.Addr().String()
+	l.Close()
+
+	d, status := newTestDispatcher(t)
+	defer d.Stop()
+
+	assert.Nil(t, d.Dispatch(context.Background(), testNotification(), testEndpoint(t, unreachable)))
+	assert.Nil(t, d.Dispatch(context.Background(), testNotification(), testEndpoint(t, server.Listener.Addr().String())))
+
+	s := waitStatus(t, status)
+	assert.Equal(t, server.Listener.Addr().String(), s.endpoint)
+	assert.Nil(t, s.err)
+
+	s = waitStatus(t, status)
+	assert.Equal(t, unreachable, s.endpoint)
+	assert.NotNil(t, s.err)
+	assert.Equal(t, 3, s.attempts)
+}
+
+func TestNotificationDispatcherCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	d, status := newTestDispatcher(t)
+	d.InitialDelay = 10 * time.Second
+	d.MaxDelay = 10 * time.Second
+	defer d.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.Nil(t, d.Dispatch(ctx, testNotification(), testEndpoint(t, server.Listener.Addr().String())))
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	s := waitStatus(t, status)
+	assert.Equal(t, context.Canceled, s.err)
+	assert.Equal(t, 1, s.attempts)
+
+	d.Stop()
+	assert.NotNil(t, d.Dispatch(context.Background(), testNotification(), testEndpoint(t, server.Listener.Addr().String())))
+}
@@ -0,0 +1,183 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/strfmt"
+	"github.com/spf13/viper"
+
+	apimodel "gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientmodel"
+)
+
+// SubscriptionParamsBuilder builds and validates clientmodel.SubscriptionParams.
+// Errors are collected on the way and returned together by Build.
+type SubscriptionParamsBuilder struct {
+	params *apimodel.SubscriptionParams
+	errs   []error
+}
+
+// NewSubscriptionParamsBuilder returns a builder with ClientEndpoint filled from
+// the xApp's own service name and http/rmrdata ports.
+func NewSubscriptionParamsBuilder() *SubscriptionParamsBuilder {
+	appnamespace := os.Getenv("APP_NAMESPACE")
+	if appnamespace == "" {
+		appnamespace = DEFAULT_XAPP_NS
+	}
+	host := fmt.Sprintf("service-%s-%s-http.%s", appnamespace, viper.GetString("name"), appnamespace)
+	httpPort := int64(GetPortData("http").Port)
+	rmrPort := int64(GetPortData("rmrdata").Port)
+
+	return &SubscriptionParamsBuilder{
+		params: &apimodel.SubscriptionParams{
+			ClientEndpoint: &apimodel.SubscriptionParamsClientEndpoint{
+				Host:     host,
+				HTTPPort: &httpPort,
+				RMRPort:  &rmrPort,
+			},
+			SubscriptionDetails: apimodel.SubscriptionDetailsList{},
+		},
+	}
+}
+
+func (b *SubscriptionParamsBuilder) WithSubscriptionID(id string) *SubscriptionParamsBuilder {
+	b.params.SubscriptionID = id
+	return b
+}
+
+func (b *SubscriptionParamsBuilder) WithMeid(meid string) *SubscriptionParamsBuilder {
+	b.params.Meid = &meid
+	return b
+}
+
+func (b *SubscriptionParamsBuilder) WithRANFunctionID(id int64) *SubscriptionParamsBuilder {
+	b.params.RANFunctionID = &id
+	return b
+}
+
+func (b *SubscriptionParamsBuilder) WithClientEndpoint(host string, httpPort, rmrPort int64) *SubscriptionParamsBuilder {
+	b.params.ClientEndpoint = &apimodel.SubscriptionParamsClientEndpoint{
+		Host:     host,
+		HTTPPort: &httpPort,
+		RMRPort:  &rmrPort,
+	}
+	return b
+}
+
+func (b *SubscriptionParamsBuilder) WithE2SubscriptionDirectives(timeout, retryCount int64, rmrRoutingNeeded bool) *SubscriptionParamsBuilder {
+	b.params.E2SubscriptionDirectives = &apimodel.SubscriptionParamsE2SubscriptionDirectives{
+		E2TimeoutTimerValue: timeout,
+		E2RetryCount:        &retryCount,
+		RMRRoutingNeeded:    rmrRoutingNeeded,
+	}
+	return b
+}
+
+// AddSubscriptionDetail starts a new subscription detail, following AddAction calls are added into it
+func (b *SubscriptionParamsBuilder) AddSubscriptionDetail(xappEventInstanceID int64, eventTriggers []int64) *SubscriptionParamsBuilder {
+	b.params.SubscriptionDetails = append(b.params.SubscriptionDetails, &apimodel.SubscriptionDetail{
+		XappEventInstanceID: &xappEventInstanceID,
+		EventTriggers:       apimodel.EventTriggerDefinition(eventTriggers),
+		ActionToBeSetupList: apimodel.ActionsToBeSetup{},
+	})
+	return b
+}
+
+// AddAction adds an action into the latest subscription detail
+func (b *SubscriptionParamsBuilder) AddAction(actionID int64, actionType string, actionDefinition []int64) *SubscriptionParamsBuilder {
+	detail := b.lastDetail()
+	if detail == nil {
+		b.errs = append(b.errs, fmt.Errorf("action %d added before any subscription detail", actionID))
+		return b
+	}
+
+	detail.ActionToBeSetupList = append(detail.ActionToBeSetupList, &apimodel.ActionToBeSetup{
+		ActionID:         &actionID,
+		ActionType:       &actionType,
+		ActionDefinition: apimodel.ActionDefinition(actionDefinition),
+	})
+	return b
+}
+
+// WithSubsequentAction sets the subsequent action of the latest action
+func (b *SubscriptionParamsBuilder) WithSubsequentAction(subsequentActionType, timeToWait string) *SubscriptionParamsBuilder {
+	detail := b.lastDetail()
+	if detail == nil || len(detail.ActionToBeSetupList) == 0 {
+		b.errs = append(b.errs, fmt.Errorf("subsequent action set before any action"))
+		return b
+	}
+
+	action := detail.ActionToBeSetupList[len(detail.ActionToBeSetupList)-1]
+	action.SubsequentAction = &apimodel.SubsequentAction{
+		SubsequentActionType: &subsequentActionType,
+		TimeToWait:           &timeToWait,
+	}
+	return b
+}
+
+// Build validates the parameters against the REST API spec
+func (b *SubscriptionParamsBuilder) Build() (*apimodel.SubscriptionParams, error) {
+	errs := append([]error{}, b.errs...)
+	if len(b.params.SubscriptionDetails) == 0 {
+		errs = append(errs, fmt.Errorf("at least one subscription detail is required"))
+	}
+	for _, d := range b.params.SubscriptionDetails {
+		if len(d.ActionToBeSetupList) == 0 {
+			errs = append(errs, fmt.Errorf("subscription detail %d has no actions", *d.XappEventInstanceID))
+		}
+	}
+	if err := b.params.Validate(strfmt.Default); err != nil {
+		errs = append(errs, flattenValidationErrors(err)...)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.CompositeValidationError(errs...)
+	}
+	return b.params, nil
+}
+
+// Subscribe builds the parameters and sends the subscription request
+func (b *SubscriptionParamsBuilder) Subscribe(s *Subscriber) (*apimodel.SubscriptionResponse, error) {
+	p, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return s.Subscribe(p)
+}
+
+func (b *SubscriptionParamsBuilder) lastDetail() *apimodel.SubscriptionDetail {
+	if len(b.params.SubscriptionDetails) == 0 {
+		return nil
+	}
+	return b.params.SubscriptionDetails[len(b.params.SubscriptionDetails)-1]
+}
+
+func flattenValidationErrors(err error) (errs []error) {
+	if c, ok := err.(*errors.CompositeError); ok {
+		for _, e := range c.Errors {
+			errs = append(errs, flattenValidationErrors(e)...)
+		}
+		return
+	}
+	return []error{err}
+}
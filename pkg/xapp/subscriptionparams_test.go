@@ -0,0 +1,80 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionParamsBuilder(t *testing.T) {
+	p, err := NewSubscriptionParamsBuilder().
+		WithMeid("gnb123456").
+		WithRANFunctionID(1).
+		WithE2SubscriptionDirectives(2, 2, true).
+		AddSubscriptionDetail(1, []int64{1, 2, 3}).
+		AddAction(1, "report", []int64{5, 6, 7, 8}).
+		WithSubsequentAction("continue", "w10ms").
+		Build()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "service-ricxapp-xapp-http.ricxapp", p.ClientEndpoint.Host)
+	assert.Equal(t, int64(8086), *p.ClientEndpoint.HTTPPort)
+	assert.Equal(t, int64(4560), *p.ClientEndpoint.RMRPort)
+	assert.Equal(t, "gnb123456", *p.Meid)
+	assert.Equal(t, 1, len(p.SubscriptionDetails))
+	assert.Equal(t, "w10ms", *p.SubscriptionDetails[0].ActionToBeSetupList[0].SubsequentAction.TimeToWait)
+}
+
+func TestSubscriptionParamsBuilderValidation(t *testing.T) {
+	_, err := NewSubscriptionParamsBuilder().
+		WithMeid("gnb123456").
+		WithRANFunctionID(4096).
+		AddAction(1, "report", nil).
+		AddSubscriptionDetail(65536, []int64{1}).
+		AddAction(256, "notify", nil).
+		WithSubsequentAction("stop", "w3ms").
+		Build()
+
+	assert.NotNil(t, err)
+	for _, s := range []string{"action 1 added before", "RANFunctionID", "XappEventInstanceId", "ActionID", "ActionType", "SubsequentActionType", "TimeToWait"} {
+		assert.True(t, strings.Contains(err.Error(), s), "missing '%s' in: %s", s, err.Error())
+	}
+
+	_, err = NewSubscriptionParamsBuilder().WithMeid("gnb123456").WithRANFunctionID(1).Build()
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), "at least one subscription detail"))
+}
+
+func TestSubscriptionParamsBuilderSubscribe(t *testing.T) {
+	resp, err := NewSubscriptionParamsBuilder().
+		WithMeid(meid).
+		WithRANFunctionID(funId).
+		WithClientEndpoint(clientEndpoint.Host, *clientEndpoint.HTTPPort, *clientEndpoint.RMRPort).
+		AddSubscriptionDetail(eventInstanceId, []int64{00, 0x11, 0x12, 0x13, 0x00, 0x21, 0x22, 0x24, 0x1B, 0x80}).
+		AddAction(actionId, actionType, []int64{5, 6, 7, 8}).
+		WithSubsequentAction(subsequestActioType, timeToWait).
+		Subscribe(Subscription)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "gnb123456-localhost", *resp.SubscriptionID)
+}
@@ -0,0 +1,110 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/models"
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/restapi/operations/common"
+)
+
+// SubscriptionQueryFilter selects the subscriptions returned by a query,
+// zero values match everything
+type SubscriptionQueryFilter struct {
+	Meid                 string
+	RANFunctionID        *int64
+	ClientEndpoint       string // host:port
+	SubscriptionIDPrefix string
+	Offset               int64
+	Limit                int64 // No limit if 0
+}
+
+func newSubscriptionQueryFilter(p common.GetAllSubscriptionsParams) SubscriptionQueryFilter {
+	f := SubscriptionQueryFilter{RANFunctionID: p.RanFunctionID}
+	if p.Meid != nil {
+		f.Meid = *p.Meid
+	}
+	if p.ClientEndpoint != nil {
+		f.ClientEndpoint = *p.ClientEndpoint
+	}
+	if p.SubscriptionIDPrefix != nil {
+		f.SubscriptionIDPrefix = *p.SubscriptionIDPrefix
+	}
+	if p.Offset != nil {
+		f.Offset = *p.Offset
+	}
+	if p.Limit != nil {
+		f.Limit = *p.Limit
+	}
+	return f
+}
+
+// Match returns true if the subscription passes all filters
+func (f SubscriptionQueryFilter) Match(s *models.SubscriptionData) bool {
+	if s == nil {
+		return false
+	}
+	if f.Meid != "" && s.Meid != f.Meid {
+		return false
+	}
+	if f.RANFunctionID != nil && (s.RANFunctionID == nil || *s.RANFunctionID != *f.RANFunctionID) {
+		return false
+	}
+	if f.SubscriptionIDPrefix != "" && !strings.HasPrefix(strconv.FormatInt(s.SubscriptionID, 10), f.SubscriptionIDPrefix) {
+		return false
+	}
+	if f.ClientEndpoint != "" {
+		for _, ep := range s.ClientEndpoint {
+			if ep == f.ClientEndpoint {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Apply returns the requested page of matching subscriptions ordered by ID,
+// and the total number of matching subscriptions
+func (f SubscriptionQueryFilter) Apply(l models.SubscriptionList) (models.SubscriptionList, int64) {
+	matching := models.SubscriptionList{}
+	for _, s := range l {
+		if f.Match(s) {
+			matching = append(matching, s)
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool { return matching[i].SubscriptionID < matching[j].SubscriptionID })
+
+	total := int64(len(matching))
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+	if f.Offset >= total {
+		return models.SubscriptionList{}, total
+	}
+	end := total
+	if f.Limit > 0 && f.Limit < total-f.Offset {
+		end = f.Offset + f.Limit
+	}
+	return matching[f.Offset:end], total
+}
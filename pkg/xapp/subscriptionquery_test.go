@@ -0,0 +1,87 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/models"
+)
+
+func TestSubscriptionQueryFilterApply(t *testing.T) {
+	funId1, funId2 := int64(1), int64(2)
+	l := models.SubscriptionList{
+		{SubscriptionID: 30, Meid: "gnb1", RANFunctionID: &funId1, ClientEndpoint: []string{"xapp1:8080"}},
+		{SubscriptionID: 10, Meid: "gnb1", RANFunctionID: &funId2, ClientEndpoint: []string{"xapp1:8080"}},
+		{SubscriptionID: 21, Meid: "gnb2", ClientEndpoint: []string{"xapp2:8080"}},
+		{SubscriptionID: 20, Meid: "gnb1", RANFunctionID: &funId1, ClientEndpoint: []string{"xapp2:8080"}},
+	}
+
+	page, total := SubscriptionQueryFilter{}.Apply(l)
+	assert.Equal(t, int64(4), total)
+	assert.Equal(t, []int64{10, 20, 21, 30}, subscriptionIds(page))
+
+	page, total = SubscriptionQueryFilter{Meid: "gnb1", RANFunctionID: &funId1}.Apply(l)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []int64{20, 30}, subscriptionIds(page))
+
+	page, total = SubscriptionQueryFilter{ClientEndpoint: "xapp2:8080", SubscriptionIDPrefix: "2"}.Apply(l)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []int64{20, 21}, subscriptionIds(page))
+
+	page, total = SubscriptionQueryFilter{Offset: 1, Limit: 2}.Apply(l)
+	assert.Equal(t, int64(4), total)
+	assert.Equal(t, []int64{20, 21}, subscriptionIds(page))
+
+	page, total = SubscriptionQueryFilter{Offset: 4}.Apply(l)
+	assert.Equal(t, int64(4), total)
+	assert.Equal(t, 0, len(page))
+
+	page, total = SubscriptionQueryFilter{Offset: 1, Limit: math.MaxInt64}.Apply(l)
+	assert.Equal(t, int64(4), total)
+	assert.Equal(t, []int64{20, 21, 30}, subscriptionIds(page))
+}
+
+func TestSubscriptionQueryWithFilter(t *testing.T) {
+	resp, total, err := Subscription.QuerySubscriptionsWithFilter(SubscriptionQueryFilter{Meid: "Test-Gnb", ClientEndpoint: "127.0.0.1:4056"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, total, int64(1))
+	assert.Equal(t, resp[0].SubscriptionID, int64(11))
+
+	resp, total, err = Subscription.QuerySubscriptionsWithFilter(SubscriptionQueryFilter{SubscriptionIDPrefix: "2"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, total, int64(0))
+	assert.Equal(t, len(resp), 0)
+
+	resp, total, err = Subscription.QuerySubscriptionsWithFilter(SubscriptionQueryFilter{Offset: 1, Limit: 10})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, total, int64(1))
+	assert.Equal(t, len(resp), 0)
+}
+
+func subscriptionIds(l models.SubscriptionList) (ids []int64) {
+	for _, s := range l {
+		ids = append(ids, s.SubscriptionID)
+	}
+	return
+}
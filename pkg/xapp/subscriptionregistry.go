@@ -0,0 +1,504 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+
+	apimodel "gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientmodel"
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/models"
+)
+
+const (
+	subscriptionRegistryKeyPrefix = "xappsubs:"
+	// Removed subscriptions whose unsubscribe failed, by subscription ID
+	subscriptionRegistryRemovedKeyPrefix = "xappsubs-removed:"
+)
+
+// ManagedSubscription is the desired state of one subscription, as persisted in SDL
+type ManagedSubscription struct {
+	Name           string                       `json:"name"`
+	Params         *apimodel.SubscriptionParams `json:"params"`
+	SubscriptionID string                       `json:"subscriptionId,omitempty"`
+}
+
+func (s *ManagedSubscription) meid() string {
+	if s.Params == nil || s.Params.Meid == nil {
+		return ""
+	}
+	return *s.Params.Meid
+}
+
+func (s *ManagedSubscription) endpoints() (eps []string) {
+	if s.Params == nil || s.Params.ClientEndpoint == nil {
+		return
+	}
+	ep := s.Params.ClientEndpoint
+	if ep.HTTPPort != nil {
+		eps = append(eps, fmt.Sprintf("%s:%d", ep.Host, *ep.HTTPPort))
+	}
+	if ep.RMRPort != nil {
+		eps = append(eps, fmt.Sprintf("%s:%d", ep.Host, *ep.RMRPort))
+	}
+	return
+}
+
+// matches tells if an entry of the Subscription Manager is this subscription. The
+// Subscription Manager lists E2 instance IDs instead of subscription IDs, so the entries
+// are matched on the MEID, the RAN function and the client endpoint.
+func (s *ManagedSubscription) matches(a *models.SubscriptionData) bool {
+	if a == nil || s.Params == nil || a.Meid != s.meid() {
+		return false
+	}
+	if a.RANFunctionID == nil || s.Params.RANFunctionID == nil || *a.RANFunctionID != *s.Params.RANFunctionID {
+		return false
+	}
+	for _, ep := range s.endpoints() {
+		for _, aep := range a.ClientEndpoint {
+			if ep == aep {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// -----------------------------------------------------------------------------
+// SubscriptionRegistry keeps the subscriptions an xApp wants to have, persists
+// them into SDL and reconciles them against the Subscription Manager
+// -----------------------------------------------------------------------------
+type SubscriptionRegistry struct {
+	mux        sync.Mutex
+	subscriber *Subscriber
+	db         *SDLStorage
+	namespace  string
+	subs       map[string]*ManagedSubscription
+	removed    map[string]*ManagedSubscription // Unsubscribe failed, by subscription ID
+
+	// Unknown subscriptions on our own endpoints are unsubscribed by Reconcile
+	deleteOrphans bool
+}
+
+func NewSubscriptionRegistry(subscriber *Subscriber, db *SDLStorage, namespace string) *SubscriptionRegistry {
+	if namespace == "" {
+		namespace = viper.GetString("controls.db.namespace")
+	}
+	if namespace == "" {
+		namespace = "sdl"
+	}
+
+	return &SubscriptionRegistry{
+		subscriber:    subscriber,
+		db:            db,
+		namespace:     namespace,
+		subs:          make(map[string]*ManagedSubscription),
+		removed:       make(map[string]*ManagedSubscription),
+		deleteOrphans: viper.GetBool("controls.subscription.registry.deleteOrphans"),
+	}
+}
+
+// SetDeleteOrphans sets whether Reconcile unsubscribes the subscriptions on our own
+// client endpoints that are not in the registry. Defaults to the configuration
+// value "controls.subscription.registry.deleteOrphans".
+func (r *SubscriptionRegistry) SetDeleteOrphans(enabled bool) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.deleteOrphans = enabled
+}
+
+// Registry returns the subscription registry of the subscriber, persisted into the
+// SDL namespace of the xApp. It is reconciled at startup and re-subscribes on RAN events.
+func (r *Subscriber) Registry() *SubscriptionRegistry {
+	r.registryMux.Lock()
+	defer r.registryMux.Unlock()
+
+	if r.registry == nil {
+		r.registry = NewSubscriptionRegistry(r, SdlStorage, "")
+	}
+	return r.registry
+}
+
+// reconcileRegistry reconciles the subscriptions persisted by an earlier run, if any
+func (r *Subscriber) reconcileRegistry() {
+	reg := r.Registry()
+	if err := reg.Load(); err != nil {
+		Logger.Error("SubscriptionRegistry: loading from SDL failed: %v", err)
+		return
+	}
+	if len(reg.List()) == 0 && !reg.hasRemoved() {
+		return
+	}
+	if err := reg.Reconcile(); err != nil {
+		Logger.Error("SubscriptionRegistry: %v", err)
+	}
+}
+
+// handleRanEvent passes the RAN events to the subscription registry
+func (r *Subscriber) handleRanEvent(params *RMRParams) {
+	if params.Mtype == RAN_CONNECTED || params.Mtype == RAN_RESTARTED {
+		r.Registry().HandleRanEvent(params)
+	}
+}
+
+// Add stores a desired subscription under the given name and subscribes it
+func (r *SubscriptionRegistry) Add(name string, p *apimodel.SubscriptionParams) (*apimodel.SubscriptionResponse, error) {
+	if name == "" || p == nil {
+		return nil, fmt.Errorf("subscription name and params are mandatory")
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	s := &ManagedSubscription{Name: name, Params: p}
+	if old, ok := r.subs[name]; ok {
+		s.SubscriptionID = old.SubscriptionID
+	}
+	r.subs[name] = s
+	if err := r.store(s); err != nil {
+		return nil, err
+	}
+	return r.subscribe(s)
+}
+
+// Remove unsubscribes the named subscription and drops it from the registry. If the
+// unsubscribe fails, it is retried by Reconcile.
+func (r *SubscriptionRegistry) Remove(name string) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	s, ok := r.subs[name]
+	if !ok {
+		return fmt.Errorf("subscription '%s' not found", name)
+	}
+	delete(r.subs, name)
+
+	if err := r.db.Delete(r.namespace, []string{subscriptionRegistryKeyPrefix + name}); err != nil {
+		Logger.Error("SubscriptionRegistry: removing '%s' from SDL failed: %v", name, err)
+	}
+
+	if s.SubscriptionID == "" {
+		return nil
+	}
+	if err := r.subscriber.Unsubscribe(s.SubscriptionID); err != nil {
+		r.markRemoved(s)
+		return err
+	}
+	return nil
+}
+
+// Get returns a copy of the named subscription
+func (r *SubscriptionRegistry) Get(name string) (ManagedSubscription, bool) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if s, ok := r.subs[name]; ok {
+		return *s, true
+	}
+	return ManagedSubscription{}, false
+}
+
+// List returns a copy of all managed subscriptions
+func (r *SubscriptionRegistry) List() (l []ManagedSubscription) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	for _, s := range r.subs {
+		l = append(l, *s)
+	}
+	return
+}
+
+// Load reads the persisted subscriptions from SDL into the registry
+func (r *SubscriptionRegistry) Load() error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.load()
+}
+
+// Reconcile loads the desired subscriptions from SDL and compares them with
+// the ones known by the Subscription Manager: missing ones are re-created and
+// the removed ones whose unsubscribe failed are unsubscribed again. Other
+// subscriptions on our own client endpoints are orphans of an earlier run:
+// they are unsubscribed if enabled with SetDeleteOrphans, otherwise only reported.
+func (r *SubscriptionRegistry) Reconcile() error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if err := r.load(); err != nil {
+		return err
+	}
+
+	active, err := r.subscriber.QuerySubscriptions()
+	if err != nil {
+		Logger.Error("SubscriptionRegistry: QuerySubscriptions failed: %v", err)
+		return err
+	}
+
+	// Each entry of the Subscription Manager is claimed by at most one subscription,
+	// preferring the entry with the same subscription ID
+	claimed := make(map[*models.SubscriptionData]bool)
+	claim := func(s *ManagedSubscription) bool {
+		for _, sameID := range []bool{true, false} {
+			for _, a := range active {
+				if claimed[a] || !s.matches(a) {
+					continue
+				}
+				if sameID && strconv.FormatInt(a.SubscriptionID, 10) != s.SubscriptionID {
+					continue
+				}
+				claimed[a] = true
+				return true
+			}
+		}
+		return false
+	}
+
+	var errs []string
+	ownEndpoints := make(map[string]bool)
+	for _, s := range sortedSubscriptions(r.subs) {
+		for _, ep := range s.endpoints() {
+			ownEndpoints[ep] = true
+		}
+		if claim(s) {
+			// Adopted from an earlier run, its notifications are ours
+			if s.SubscriptionID != "" {
+				r.subscriber.recordRequest(s.SubscriptionID, s.Params, false)
+			}
+			continue
+		}
+
+		Logger.Info("SubscriptionRegistry: subscription '%s' (id=%s) missing, re-creating", s.Name, s.SubscriptionID)
+		if _, err := r.subscribe(s); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", s.Name, err))
+		}
+	}
+
+	for _, s := range sortedSubscriptions(r.removed) {
+		if !claim(s) {
+			// Already gone from the Subscription Manager
+			r.unmarkRemoved(s)
+			continue
+		}
+
+		Logger.Info("SubscriptionRegistry: unsubscribing removed subscription '%s' (id=%s)", s.Name, s.SubscriptionID)
+		if err := r.subscriber.Unsubscribe(s.SubscriptionID); err != nil {
+			errs = append(errs, fmt.Sprintf("removed %s: %v", s.SubscriptionID, err))
+			continue
+		}
+		r.unmarkRemoved(s)
+	}
+
+	for _, a := range active {
+		if a == nil || claimed[a] || !hasOwnEndpoint(a.ClientEndpoint, ownEndpoints) {
+			continue
+		}
+		if !r.deleteOrphans {
+			Logger.Warn("SubscriptionRegistry: unknown subscription on own endpoint: id=%d meid=%s", a.SubscriptionID, a.Meid)
+			continue
+		}
+
+		id := strconv.FormatInt(a.SubscriptionID, 10)
+		Logger.Info("SubscriptionRegistry: unsubscribing orphan subscription id=%s meid=%s", id, a.Meid)
+		if err := r.subscriber.Unsubscribe(id); err != nil {
+			errs = append(errs, fmt.Sprintf("orphan %s: %v", id, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("reconcile failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Resubscribe re-creates all subscriptions towards the given MEID
+func (r *SubscriptionRegistry) Resubscribe(meid string) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	var errs []string
+	for _, s := range r.subs {
+		if s.meid() != meid {
+			continue
+		}
+
+		if s.SubscriptionID != "" {
+			if err := r.subscriber.Unsubscribe(s.SubscriptionID); err != nil {
+				Logger.Debug("SubscriptionRegistry: unsubscribe of stale id=%s failed: %v", s.SubscriptionID, err)
+			}
+			s.SubscriptionID = ""
+		}
+
+		Logger.Info("SubscriptionRegistry: re-subscribing '%s' for meid=%s", s.Name, meid)
+		if _, err := r.subscribe(s); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", s.Name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("resubscribe failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// HandleRanEvent triggers re-subscription on RAN_CONNECTED and RAN_RESTARTED
+// messages. Returns true if the message was a RAN event.
+func (r *SubscriptionRegistry) HandleRanEvent(params *RMRParams) bool {
+	if params == nil || (params.Mtype != RAN_CONNECTED && params.Mtype != RAN_RESTARTED) {
+		return false
+	}
+	if params.Meid == nil || params.Meid.RanName == "" {
+		return true
+	}
+
+	// Don't block the RMR receive path with REST calls
+	go func(meid string) {
+		if err := r.Resubscribe(meid); err != nil {
+			Logger.Error("SubscriptionRegistry: %v", err)
+		}
+	}(params.Meid.RanName)
+
+	return true
+}
+
+func (r *SubscriptionRegistry) subscribe(s *ManagedSubscription) (*apimodel.SubscriptionResponse, error) {
+	resp, err := r.subscriber.Subscribe(s.Params)
+	if err != nil {
+		Logger.Error("SubscriptionRegistry: subscribe '%s' failed: %v", s.Name, err)
+		return resp, err
+	}
+
+	if resp != nil && resp.SubscriptionID != nil {
+		s.SubscriptionID = *resp.SubscriptionID
+	}
+	return resp, r.store(s)
+}
+
+func (r *SubscriptionRegistry) store(s *ManagedSubscription) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	if err := r.db.Store(r.namespace, subscriptionRegistryKeyPrefix+s.Name, string(b)); err != nil {
+		Logger.Error("SubscriptionRegistry: storing '%s' to SDL failed: %v", s.Name, err)
+		return err
+	}
+	return nil
+}
+
+func (r *SubscriptionRegistry) load() error {
+	keys, err := r.db.ReadAllKeys(r.namespace)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, k := range keys {
+		if strings.HasPrefix(k, subscriptionRegistryKeyPrefix) || strings.HasPrefix(k, subscriptionRegistryRemovedKeyPrefix) {
+			names = append(names, k)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	values, err := r.db.MRead(r.namespace, names)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range values {
+		var data []byte
+		switch d := v.(type) {
+		case string:
+			data = []byte(d)
+		case []byte:
+			data = d
+		default:
+			continue
+		}
+
+		s := &ManagedSubscription{}
+		if err := json.Unmarshal(data, s); err != nil {
+			Logger.Error("SubscriptionRegistry: invalid entry '%s' in SDL: %v", k, err)
+			continue
+		}
+		if strings.HasPrefix(k, subscriptionRegistryRemovedKeyPrefix) {
+			r.removed[s.SubscriptionID] = s
+		} else {
+			r.subs[s.Name] = s
+		}
+	}
+	return nil
+}
+
+func (r *SubscriptionRegistry) hasRemoved() bool {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return len(r.removed) > 0
+}
+
+// markRemoved persists a removed subscription whose unsubscribe failed
+func (r *SubscriptionRegistry) markRemoved(s *ManagedSubscription) {
+	r.removed[s.SubscriptionID] = s
+	b, err := json.Marshal(s)
+	if err == nil {
+		err = r.db.Store(r.namespace, subscriptionRegistryRemovedKeyPrefix+s.SubscriptionID, string(b))
+	}
+	if err != nil {
+		Logger.Error("SubscriptionRegistry: storing removed '%s' to SDL failed: %v", s.Name, err)
+	}
+}
+
+func (r *SubscriptionRegistry) unmarkRemoved(s *ManagedSubscription) {
+	delete(r.removed, s.SubscriptionID)
+	if err := r.db.Delete(r.namespace, []string{subscriptionRegistryRemovedKeyPrefix + s.SubscriptionID}); err != nil {
+		Logger.Error("SubscriptionRegistry: removing '%s' from SDL failed: %v", s.SubscriptionID, err)
+	}
+}
+
+func sortedSubscriptions(subs map[string]*ManagedSubscription) (l []*ManagedSubscription) {
+	for _, s := range subs {
+		l = append(l, s)
+	}
+	sort.Slice(l, func(i, j int) bool {
+		if l[i].Name != l[j].Name {
+			return l[i].Name < l[j].Name
+		}
+		return l[i].SubscriptionID < l[j].SubscriptionID
+	})
+	return
+}
+
+func hasOwnEndpoint(endpoints []string, own map[string]bool) bool {
+	for _, ep := range endpoints {
+		if own[ep] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientmodel"
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/submgrfake"
+)
+
+func TestSubscriptionRegistryAdd(t *testing.T) {
+	reg := NewSubscriptionRegistry(Subscription, SdlStorage, "subregtest")
+
+	resp, err := reg.Add("sub1", GetSubscriptionparams())
+	assert.Nil(t, err)
+	assert.Equal(t, "gnb123456-localhost", *resp.SubscriptionID)
+
+	s, ok := reg.Get("sub1")
+	assert.True(t, ok)
+	assert.Equal(t, "gnb123456-localhost", s.SubscriptionID)
+
+	// A fresh registry finds the same subscription from SDL
+	reg2 := NewSubscriptionRegistry(Subscription, SdlStorage, "subregtest")
+	assert.Nil(t, reg2.Load())
+	s, ok = reg2.Get("sub1")
+	assert.True(t, ok)
+	assert.Equal(t, "gnb123456-localhost", s.SubscriptionID)
+	assert.Equal(t, meid, *s.Params.Meid)
+}
+
+func TestSubscriptionRegistryAddInvalid(t *testing.T) {
+	reg := NewSubscriptionRegistry(Subscription, SdlStorage, "subregtest")
+
+	_, err := reg.Add("", GetSubscriptionparams())
+	assert.NotNil(t, err)
+	_, err = reg.Add("sub2", nil)
+	assert.NotNil(t, err)
+	assert.NotNil(t, reg.Remove("unknown"))
+}
+
+// newTestSubscriptionRegistry returns a registry in an own SDL namespace, backed by a fake
+// Subscription Manager that doesn't notify
+func newTestSubscriptionRegistry(t *testing.T) (*SubscriptionRegistry, *submgrfake.SubMgr) {
+	fake := submgrfake.New()
+	assert.Nil(t, fake.Start(""))
+	namespace := "subregtest-" + t.Name()
+	SdlStorage.Clear(namespace)
+	t.Cleanup(func() {
+		fake.Stop()
+		SdlStorage.Clear(namespace)
+	})
+	return NewSubscriptionRegistry(NewSubscriber(fake.Host(), 2), SdlStorage, namespace), fake
+}
+
+func TestSubscriptionRegistryReconcile(t *testing.T) {
+	reg, fake := newTestSubscriptionRegistry(t)
+	fake.ScriptSubscribe(submgrfake.Outcome{NoNotification: true}, submgrfake.Outcome{NoNotification: true})
+
+	_, err := reg.Add("sub1", GetSubscriptionparams())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fake.Subscriptions()))
+
+	// The subscription is found in the Subscription Manager and not re-created
+	assert.Nil(t, reg.Reconcile())
+	assert.Equal(t, 1, len(fake.Subscriptions()))
+
+	// After a Subscription Manager restart a fresh registry re-creates it from SDL
+	fake.Reset()
+	fake.ScriptSubscribe(submgrfake.Outcome{NoNotification: true})
+	reg2 := NewSubscriptionRegistry(reg.subscriber, SdlStorage, reg.namespace)
+	assert.Nil(t, reg2.Reconcile())
+	assert.Equal(t, 1, len(fake.Subscriptions()))
+
+	s, ok := reg2.Get("sub1")
+	assert.True(t, ok)
+	_, ok = fake.Subscription(s.SubscriptionID)
+	assert.True(t, ok)
+}
+
+func TestSubscriptionRegistryReconcileRemoved(t *testing.T) {
+	reg, fake := newTestSubscriptionRegistry(t)
+	fake.ScriptSubscribe(submgrfake.Outcome{NoNotification: true})
+
+	_, err := reg.Add("sub1", GetSubscriptionparams())
+	assert.Nil(t, err)
+
+	// The failed unsubscribe is retried by a later Reconcile with the subscription ID
+	fake.ScriptUnsubscribe(submgrfake.Outcome{StatusCode: 500})
+	assert.NotNil(t, reg.Remove("sub1"))
+	assert.Equal(t, 1, len(fake.Subscriptions()))
+
+	reg2 := NewSubscriptionRegistry(reg.subscriber, SdlStorage, reg.namespace)
+	assert.Nil(t, reg2.Reconcile())
+	assert.Equal(t, 0, len(fake.Subscriptions()))
+	assert.Equal(t, 0, len(reg2.List()))
+	assert.False(t, reg2.hasRemoved())
+}
+
+func TestSubscriptionRegistryReconcileForeign(t *testing.T) {
+	reg, fake := newTestSubscriptionRegistry(t)
+	fake.ScriptSubscribe(submgrfake.Outcome{NoNotification: true}, submgrfake.Outcome{NoNotification: true})
+
+	// A subscription of another client is left alone
+	other := GetSubscriptionparams()
+	other.ClientEndpoint = &clientmodel.SubscriptionParamsClientEndpoint{Host: "otherxapp", HTTPPort: other.ClientEndpoint.HTTPPort}
+	_, err := reg.subscriber.Subscribe(other)
+	assert.Nil(t, err)
+
+	_, err = reg.Add("sub1", GetSubscriptionparams())
+	assert.Nil(t, err)
+	assert.Nil(t, reg.Reconcile())
+	assert.Equal(t, 2, len(fake.Subscriptions()))
+}
+
+func TestSubscriptionRegistryReconcileAdopted(t *testing.T) {
+	reg, fake := newTestSubscriptionRegistry(t)
+	fake.ScriptSubscribe(submgrfake.Outcome{NoNotification: true})
+
+	_, err := reg.Add("sub1", GetSubscriptionparams())
+	assert.Nil(t, err)
+	s, _ := reg.Get("sub1")
+
+	// After an xApp restart the adopted subscription is tracked by the new subscriber
+	restarted := NewSubscriber(fake.Host(), 2)
+	assert.False(t, restarted.isOutstanding(s.SubscriptionID))
+	reg2 := NewSubscriptionRegistry(restarted, SdlStorage, reg.namespace)
+	assert.Nil(t, reg2.Reconcile())
+	assert.Equal(t, 1, len(fake.Subscriptions()))
+	assert.True(t, restarted.isOutstanding(s.SubscriptionID))
+}
+
+func TestSubscriptionRegistryReconcileOrphans(t *testing.T) {
+	reg, fake := newTestSubscriptionRegistry(t)
+	fake.ScriptSubscribe(submgrfake.Outcome{NoNotification: true}, submgrfake.Outcome{NoNotification: true})
+
+	// A subscription on our own endpoint that is not in the registry
+	_, err := reg.subscriber.Subscribe(GetSubscriptionparams())
+	assert.Nil(t, err)
+	_, err = reg.Add("sub1", GetSubscriptionparams())
+	assert.Nil(t, err)
+
+	// Only reported by default
+	assert.Nil(t, reg.Reconcile())
+	assert.Equal(t, 2, len(fake.Subscriptions()))
+
+	reg.SetDeleteOrphans(true)
+	assert.Nil(t, reg.Reconcile())
+	assert.Equal(t, 1, len(fake.Subscriptions()))
+
+	s, _ := reg.Get("sub1")
+	_, ok := fake.Subscription(s.SubscriptionID)
+	assert.True(t, ok)
+}
+
+func TestSubscriptionRegistryHandleRanEvent(t *testing.T) {
+	reg := NewSubscriptionRegistry(Subscription, SdlStorage, "subregtest")
+
+	assert.False(t, reg.HandleRanEvent(&RMRParams{Mtype: RIC_SUB_RESP}))
+	assert.True(t, reg.HandleRanEvent(&RMRParams{Mtype: RAN_CONNECTED, Meid: &RMRMeid{}}))
+	assert.True(t, reg.HandleRanEvent(&RMRParams{Mtype: RAN_RESTARTED}))
+}
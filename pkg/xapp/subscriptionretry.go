@@ -0,0 +1,252 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+
+	apimodel "gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientmodel"
+)
+
+var SubscriptionRetryCounterOpts = []CounterOpts{
+	{Name: "RetriesExhausted", Help: "The total number of subscription instances failed after all retries"},
+}
+
+var subscriptionRetryLabeledOpts = CounterOpts{Name: "Retries", Help: "The total number of subscription retries per error source"}
+
+// SubscriptionRetryPolicy defines how subscription instances failed due to a
+// timeout (E2-Timeout, RTMGR-Timeout, DBAAS-Timeout) are re-issued
+type SubscriptionRetryPolicy struct {
+	MaxRetries   int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	TimeoutTypes []string // Retried timeout types, all if empty
+}
+
+// NewSubscriptionRetryPolicy reads the policy from controls.subscription.retryPolicy,
+// returns nil if retries are not enabled
+func NewSubscriptionRetryPolicy() *SubscriptionRetryPolicy {
+	maxRetries := viper.GetInt("controls.subscription.retryPolicy.maxRetries")
+	if maxRetries <= 0 {
+		return nil
+	}
+
+	p := &SubscriptionRetryPolicy{
+		MaxRetries:   maxRetries,
+		InitialDelay: time.Duration(viper.GetInt("controls.subscription.retryPolicy.initialDelay")) * time.Millisecond,
+		MaxDelay:     time.Duration(viper.GetInt("controls.subscription.retryPolicy.maxDelay")) * time.Millisecond,
+		Multiplier:   viper.GetFloat64("controls.subscription.retryPolicy.multiplier"),
+		TimeoutTypes: viper.GetStringSlice("controls.subscription.retryPolicy.timeoutTypes"),
+	}
+	if p.InitialDelay == 0 {
+		p.InitialDelay = 1 * time.Second
+	}
+	if p.MaxDelay == 0 {
+		p.MaxDelay = 30 * time.Second
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	return p
+}
+
+func (p *SubscriptionRetryPolicy) delay(attempt int) time.Duration {
+	d := float64(p.InitialDelay)
+	for i := 1; i < attempt; i++ {
+		d *= p.Multiplier
+	}
+	if p.MaxDelay > 0 && time.Duration(d) > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return time.Duration(d)
+}
+
+func (p *SubscriptionRetryPolicy) isRetryable(inst *apimodel.SubscriptionInstance) bool {
+	if inst == nil || inst.TimeoutType == "" || inst.XappEventInstanceID == nil {
+		return false
+	}
+	if len(p.TimeoutTypes) == 0 {
+		return true
+	}
+	for _, t := range p.TimeoutTypes {
+		if t == inst.TimeoutType {
+			return true
+		}
+	}
+	return false
+}
+
+// subscriptionRetryState keeps the original request until every subscription
+// detail has got its final outcome
+type subscriptionRetryState struct {
+	params    *apimodel.SubscriptionParams
+	attempts  map[int64]int
+	remaining map[int64]bool
+}
+
+// SetRetryPolicy enables automatic retries of timed out subscription instances, nil disables
+func (r *Subscriber) SetRetryPolicy(p *SubscriptionRetryPolicy) {
+	r.retryMux.Lock()
+	defer r.retryMux.Unlock()
+
+	r.retryPolicy = p
+	if p != nil && r.retryStat == nil {
+		r.retryStat = Metric.RegisterCounterGroup(SubscriptionRetryCounterOpts, "Subscription")
+	}
+}
+
+func (r *Subscriber) trackRetries(id string, p *apimodel.SubscriptionParams) {
+	r.retryMux.Lock()
+	defer r.retryMux.Unlock()
+
+	if r.retryPolicy == nil || p == nil {
+		return
+	}
+	if _, ok := r.retries[id]; ok {
+		// Retry of a subscription already tracked
+		return
+	}
+
+	state := &subscriptionRetryState{
+		params:    p,
+		attempts:  make(map[int64]int),
+		remaining: make(map[int64]bool),
+	}
+	for _, d := range p.SubscriptionDetails {
+		if d != nil && d.XappEventInstanceID != nil {
+			state.remaining[*d.XappEventInstanceID] = true
+		}
+	}
+	r.retries[id] = state
+}
+
+func (r *Subscriber) untrackRetries(id string) {
+	r.retryMux.Lock()
+	defer r.retryMux.Unlock()
+	delete(r.retries, id)
+}
+
+// applyRetryPolicy re-issues the retryable failed instances of a notification and
+// returns the responses holding final outcomes, to be delivered to the xApp
+func (r *Subscriber) applyRetryPolicy(resp *apimodel.SubscriptionResponse) []*apimodel.SubscriptionResponse {
+	r.retryMux.Lock()
+	defer r.retryMux.Unlock()
+
+	if r.retryPolicy == nil || resp.SubscriptionID == nil {
+		return []*apimodel.SubscriptionResponse{resp}
+	}
+	id := *resp.SubscriptionID
+	state, ok := r.retries[id]
+	if !ok {
+		return []*apimodel.SubscriptionResponse{resp}
+	}
+
+	var final []*apimodel.SubscriptionInstance
+	var retry []*apimodel.SubscriptionDetail
+	for _, inst := range resp.SubscriptionInstances {
+		if r.retryPolicy.isRetryable(inst) && state.attempts[*inst.XappEventInstanceID] < r.retryPolicy.MaxRetries {
+			if d := state.detail(*inst.XappEventInstanceID); d != nil {
+				state.attempts[*inst.XappEventInstanceID]++
+				retry = append(retry, d)
+				Metric.RegisterLabeledCounter(subscriptionRetryLabeledOpts, []string{"source"}, []string{errorSource(inst)}, "Subscription").Inc()
+				continue
+			}
+		}
+		if r.retryPolicy.isRetryable(inst) {
+			r.retryStat["RetriesExhausted"].Inc()
+		}
+		if inst != nil && inst.XappEventInstanceID != nil {
+			delete(state.remaining, *inst.XappEventInstanceID)
+		}
+		final = append(final, inst)
+	}
+
+	if len(retry) > 0 {
+		attempt := 0
+		for _, d := range retry {
+			if a := state.attempts[*d.XappEventInstanceID]; a > attempt {
+				attempt = a
+			}
+		}
+		p := *state.params
+		p.SubscriptionID = id
+		p.SubscriptionDetails = retry
+		delay := r.retryPolicy.delay(attempt)
+
+		Logger.Info("Subscription %s: retrying %d instance(s) in %v, attempt %d", id, len(retry), delay, attempt)
+		time.AfterFunc(delay, func() { r.resubscribe(id, &p) })
+	}
+
+	if len(state.remaining) == 0 {
+		delete(r.retries, id)
+	}
+
+	if len(final) == 0 {
+		return nil
+	}
+	return []*apimodel.SubscriptionResponse{{SubscriptionID: resp.SubscriptionID, SubscriptionInstances: final}}
+}
+
+// resubscribe re-issues the failed subscription details, if the request itself
+// fails the details are reported as failed to the xApp
+func (r *Subscriber) resubscribe(id string, p *apimodel.SubscriptionParams) {
+	// The status and the retry state keep all details of the subscription
+	r.beginRequest()
+	_, err := r.subscribe(p)
+	r.endRequest()
+	if err == nil {
+		return
+	}
+
+	Logger.Error("Subscription %s: retry failed, giving up", id)
+	var instances []*apimodel.SubscriptionInstance
+	for _, d := range p.SubscriptionDetails {
+		e2InstanceId := int64(0)
+		instances = append(instances, &apimodel.SubscriptionInstance{
+			XappEventInstanceID: d.XappEventInstanceID,
+			E2EventInstanceID:   &e2InstanceId,
+			ErrorCause:          "Subscription retry request failed",
+			ErrorSource:         apimodel.SubscriptionInstanceErrorSourceSUBMGR,
+		})
+	}
+
+	r.untrackRetries(id)
+	resp := &apimodel.SubscriptionResponse{SubscriptionID: &id, SubscriptionInstances: instances}
+	r.deliverResponse(responseContext(resp), resp)
+}
+
+func (s *subscriptionRetryState) detail(xappEventInstanceID int64) *apimodel.SubscriptionDetail {
+	for _, d := range s.params.SubscriptionDetails {
+		if d != nil && d.XappEventInstanceID != nil && *d.XappEventInstanceID == xappEventInstanceID {
+			return d
+		}
+	}
+	return nil
+}
+
+func errorSource(inst *apimodel.SubscriptionInstance) string {
+	if inst.ErrorSource == "" {
+		return "unknown"
+	}
+	return inst.ErrorSource
+}
@@ -0,0 +1,124 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientmodel"
+	"gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/submgrfake"
+)
+
+func TestSubscriptionRetryPolicyDelay(t *testing.T) {
+	p := &SubscriptionRetryPolicy{MaxRetries: 3, InitialDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond, Multiplier: 2}
+
+	assert.Equal(t, 100*time.Millisecond, p.delay(1))
+	assert.Equal(t, 200*time.Millisecond, p.delay(2))
+	assert.Equal(t, 300*time.Millisecond, p.delay(3))
+
+	assert.True(t, p.isRetryable(&clientmodel.SubscriptionInstance{XappEventInstanceID: &eventInstanceId, TimeoutType: "E2-Timeout"}))
+	assert.False(t, p.isRetryable(&clientmodel.SubscriptionInstance{XappEventInstanceID: &eventInstanceId, ErrorCause: "Some error"}))
+
+	p.TimeoutTypes = []string{"RTMGR-Timeout"}
+	assert.False(t, p.isRetryable(&clientmodel.SubscriptionInstance{XappEventInstanceID: &eventInstanceId, TimeoutType: "E2-Timeout"}))
+}
+
+func TestSubscriptionRetryOnTimeout(t *testing.T) {
+	Subscription.SetRetryPolicy(&SubscriptionRetryPolicy{MaxRetries: 1, InitialDelay: 10 * time.Millisecond, Multiplier: 2})
+	defer Subscription.SetRetryPolicy(nil)
+
+	notified := make(chan *clientmodel.SubscriptionResponse, 10)
+	Subscription.SetResponseCB(func(resp *clientmodel.SubscriptionResponse) {
+		notified <- resp
+	})
+	defer Subscription.SetResponseCB(SubscriptionRespHandler)
+
+	resp, err := Subscription.Subscribe(GetSubscriptionparams())
+	assert.Nil(t, err)
+	id := *resp.SubscriptionID
+
+	// Drain the successful notification of the subscription itself
+	select {
+	case <-notified:
+	case <-time.After(2 * time.Second):
+		t.Fatal("notification not received")
+	}
+
+	// First timeout is retried and not delivered to the xApp
+	e2InstanceId := int64(0)
+	failure := &clientmodel.SubscriptionResponse{
+		SubscriptionID: &id,
+		SubscriptionInstances: []*clientmodel.SubscriptionInstance{
+			{XappEventInstanceID: &eventInstanceId, E2EventInstanceID: &e2InstanceId, ErrorSource: "E2Node", TimeoutType: "E2-Timeout"},
+		},
+	}
+	Subscription.retryMux.Lock()
+	Subscription.retries[id] = &subscriptionRetryState{params: GetSubscriptionparams(), attempts: map[int64]int{}, remaining: map[int64]bool{eventInstanceId: true}}
+	Subscription.retryMux.Unlock()
+	assert.Equal(t, 0, len(Subscription.applyRetryPolicy(failure)))
+
+	// The retried request creates a new notification from the fake submgr
+	select {
+	case <-notified:
+	case <-time.After(2 * time.Second):
+		t.Fatal("retry not sent")
+	}
+
+	// Retries exhausted, the failure is final
+	final := Subscription.applyRetryPolicy(failure)
+	assert.Equal(t, 1, len(final))
+	assert.Equal(t, "E2-Timeout", final[0].SubscriptionInstances[0].TimeoutType)
+
+	metrics, _ := Resource.GetLocalMetrics()
+	assert.True(t, strings.Contains(metrics, `ricxapp_Subscription_Retries{source="E2Node"} 1`))
+	assert.True(t, strings.Contains(metrics, "ricxapp_Subscription_RetriesExhausted 1"))
+}
+
+func TestSubscriptionRetryKeepsStatus(t *testing.T) {
+	fake := submgrfake.New()
+	assert.Nil(t, fake.Start(""))
+	defer fake.Stop()
+	fake.ScriptSubscribe(submgrfake.Outcome{NoNotification: true}, submgrfake.Outcome{NoNotification: true})
+
+	r := NewSubscriber(fake.Host(), 2)
+	p := GetSubscriptionparams()
+	other := *p.SubscriptionDetails[0]
+	otherId := eventInstanceId + 1
+	other.XappEventInstanceID = &otherId
+	p.SubscriptionDetails = append(p.SubscriptionDetails, &other)
+
+	resp, err := r.Subscribe(p)
+	assert.Nil(t, err)
+	id := *resp.SubscriptionID
+
+	// Retrying one of the details doesn't replace the tracked subscription details
+	retry := *p
+	retry.SubscriptionID = id
+	retry.SubscriptionDetails = p.SubscriptionDetails[:1]
+	r.resubscribe(id, &retry)
+
+	status := r.SubscriptionStatus()
+	assert.Equal(t, 1, len(status))
+	assert.Equal(t, 2, len(status[0].Params.SubscriptionDetails))
+}
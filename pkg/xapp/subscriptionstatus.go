@@ -0,0 +1,211 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	apimodel "gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientmodel"
+)
+
+const (
+	SubscriptionStateRequested = "requested" // Waiting for notifications
+	SubscriptionStateActive    = "active"    // All instances created
+	SubscriptionStatePartial   = "partial"   // Some instances failed
+	SubscriptionStateFailed    = "failed"    // All instances failed
+)
+
+// SubscriptionStatus is what the xApp believes about one of its subscriptions
+type SubscriptionStatus struct {
+	SubscriptionID string                           `json:"subscriptionId"`
+	Params         *apimodel.SubscriptionParams     `json:"params"`
+	State          string                           `json:"state"`
+	Instances      []*apimodel.SubscriptionInstance `json:"instances"`
+	LastError      string                           `json:"lastError,omitempty"`
+	Created        time.Time                        `json:"created"`
+	Updated        time.Time                        `json:"updated"`
+}
+
+// SubscriptionStatus returns the subscriptions created through this Subscriber, ordered by ID
+func (r *Subscriber) SubscriptionStatus() []SubscriptionStatus {
+	r.statusMux.Lock()
+	defer r.statusMux.Unlock()
+
+	l := []SubscriptionStatus{}
+	for _, s := range r.status {
+		c := *s
+		c.Instances = append([]*apimodel.SubscriptionInstance{}, s.Instances...)
+		l = append(l, c)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].SubscriptionID < l[j].SubscriptionID })
+	return l
+}
+
+func (r *Subscriber) statusHandler(w http.ResponseWriter, req *http.Request) {
+	respondWithJSON(w, http.StatusOK, r.SubscriptionStatus())
+}
+
+// recordRequest starts tracking a subscription. A modification replaces the subscription
+// details and clears the instances, like the PUT replaces them in the Subscription Manager.
+// Otherwise the details are merged by XappEventInstanceId, as a subscribe request with a
+// known subscription ID (re)creates only the details it carries, and the instances are
+// kept since notifications may arrive before the response.
+func (r *Subscriber) recordRequest(id string, p *apimodel.SubscriptionParams, modified bool) {
+	r.statusMux.Lock()
+	defer r.statusMux.Unlock()
+
+	s := r.statusOf(id)
+	if modified || s.Params == nil || p == nil {
+		s.Params = p
+	} else {
+		s.Params = mergeDetails(s.Params, p)
+	}
+	if modified {
+		s.Instances = nil
+		s.LastError = ""
+	}
+	s.State = s.state()
+	s.Updated = time.Now()
+}
+
+func (r *Subscriber) recordResponse(resp *apimodel.SubscriptionResponse) {
+	if resp == nil || resp.SubscriptionID == nil {
+		return
+	}
+
+	r.statusMux.Lock()
+	defer r.statusMux.Unlock()
+
+	// Only the subscriptions requested by this xApp are tracked
+	s, ok := r.status[*resp.SubscriptionID]
+	if !ok {
+		return
+	}
+	for _, inst := range resp.SubscriptionInstances {
+		if inst == nil {
+			continue
+		}
+		s.Instances = replaceInstance(s.Instances, inst)
+		if instanceFailed(inst) {
+			s.LastError = inst.ErrorCause
+			if s.LastError == "" {
+				s.LastError = inst.ErrorSource + " " + inst.TimeoutType
+			}
+		}
+	}
+	s.State = s.state()
+	s.Updated = time.Now()
+}
+
+func (r *Subscriber) statusOf(id string) *SubscriptionStatus {
+	s, ok := r.status[id]
+	if !ok {
+		now := time.Now()
+		s = &SubscriptionStatus{SubscriptionID: id, State: SubscriptionStateRequested, Created: now, Updated: now}
+		r.status[id] = s
+	}
+	return s
+}
+
+func (r *Subscriber) recordError(id string, err error) {
+	r.statusMux.Lock()
+	defer r.statusMux.Unlock()
+
+	if s, ok := r.status[id]; ok && err != nil {
+		s.LastError = err.Error()
+		s.Updated = time.Now()
+	}
+}
+
+func (r *Subscriber) forget(id string) {
+	r.statusMux.Lock()
+	defer r.statusMux.Unlock()
+	delete(r.status, id)
+}
+
+func (s *SubscriptionStatus) state() string {
+	expected := 1
+	if s.Params != nil && len(s.Params.SubscriptionDetails) > 0 {
+		expected = len(s.Params.SubscriptionDetails)
+	}
+
+	failed := 0
+	for _, inst := range s.Instances {
+		if instanceFailed(inst) {
+			failed++
+		}
+	}
+
+	switch {
+	case failed > 0 && failed < len(s.Instances):
+		return SubscriptionStatePartial
+	case len(s.Instances) < expected:
+		if failed > 0 {
+			return SubscriptionStatePartial
+		}
+		return SubscriptionStateRequested
+	case failed > 0:
+		return SubscriptionStateFailed
+	default:
+		return SubscriptionStateActive
+	}
+}
+
+// replaceInstance replaces the instance with the same XappEventInstanceId, e.g. after a retry
+func replaceInstance(l []*apimodel.SubscriptionInstance, inst *apimodel.SubscriptionInstance) []*apimodel.SubscriptionInstance {
+	if inst.XappEventInstanceID != nil {
+		for i, old := range l {
+			if old.XappEventInstanceID != nil && *old.XappEventInstanceID == *inst.XappEventInstanceID {
+				l[i] = inst
+				return l
+			}
+		}
+	}
+	return append(l, inst)
+}
+
+// mergeDetails returns the params p with the details of old that p doesn't replace
+func mergeDetails(old, p *apimodel.SubscriptionParams) *apimodel.SubscriptionParams {
+	merged := *p
+	merged.SubscriptionDetails = append(apimodel.SubscriptionDetailsList{}, old.SubscriptionDetails...)
+	for _, d := range p.SubscriptionDetails {
+		merged.SubscriptionDetails = replaceDetail(merged.SubscriptionDetails, d)
+	}
+	return &merged
+}
+
+// replaceDetail replaces the detail with the same XappEventInstanceId
+func replaceDetail(l apimodel.SubscriptionDetailsList, d *apimodel.SubscriptionDetail) apimodel.SubscriptionDetailsList {
+	if d != nil && d.XappEventInstanceID != nil {
+		for i, old := range l {
+			if old != nil && old.XappEventInstanceID != nil && *old.XappEventInstanceID == *d.XappEventInstanceID {
+				l[i] = d
+				return l
+			}
+		}
+	}
+	return append(l, d)
+}
+
+func instanceFailed(inst *apimodel.SubscriptionInstance) bool {
+	return inst.ErrorCause != "" || inst.ErrorSource != "" || inst.TimeoutType != ""
+}
@@ -0,0 +1,131 @@
+/*
+==================================================================================
+  Copyright (c) 2019 AT&T Intellectual Property.
+  Copyright (c) 2019 Nokia
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+==================================================================================
+*/
+
+package xapp
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apimodel "gerrit.o-ran-sc.org/r/ric-plt/xapp-frame/pkg/clientmodel"
+)
+
+func statusInstance(xappId, e2Id int64, cause string) *apimodel.SubscriptionInstance {
+	return &apimodel.SubscriptionInstance{XappEventInstanceID: &xappId, E2EventInstanceID: &e2Id, ErrorCause: cause}
+}
+
+func TestSubscriptionStatusTracking(t *testing.T) {
+	r := &Subscriber{status: make(map[string]*SubscriptionStatus)}
+
+	p := GetSubscriptionparams()
+	p.SubscriptionDetails = append(p.SubscriptionDetails, p.SubscriptionDetails[0])
+	id := "status-1"
+
+	// Notifications of unknown subscriptions are not tracked
+	r.recordResponse(&apimodel.SubscriptionResponse{SubscriptionID: &id, SubscriptionInstances: []*apimodel.SubscriptionInstance{statusInstance(1, 11, "")}})
+	assert.Equal(t, 0, len(r.SubscriptionStatus()))
+
+	r.recordRequest(id, p, false)
+	r.recordResponse(&apimodel.SubscriptionResponse{SubscriptionID: &id, SubscriptionInstances: []*apimodel.SubscriptionInstance{statusInstance(1, 11, "")}})
+	l := r.SubscriptionStatus()
+	assert.Equal(t, 1, len(l))
+	assert.Equal(t, SubscriptionStateRequested, l[0].State)
+	assert.Equal(t, 1, len(l[0].Instances))
+
+	r.recordResponse(&apimodel.SubscriptionResponse{SubscriptionID: &id, SubscriptionInstances: []*apimodel.SubscriptionInstance{statusInstance(2, 0, "E2 node rejected")}})
+	l = r.SubscriptionStatus()
+	assert.Equal(t, SubscriptionStatePartial, l[0].State)
+	assert.Equal(t, "E2 node rejected", l[0].LastError)
+
+	// Successful retry replaces the failed instance
+	r.recordResponse(&apimodel.SubscriptionResponse{SubscriptionID: &id, SubscriptionInstances: []*apimodel.SubscriptionInstance{statusInstance(2, 12, "")}})
+	l = r.SubscriptionStatus()
+	assert.Equal(t, SubscriptionStateActive, l[0].State)
+	assert.Equal(t, 2, len(l[0].Instances))
+
+	r.recordError(id, errors.New("modify failed"))
+	assert.Equal(t, "modify failed", r.SubscriptionStatus()[0].LastError)
+
+	r.recordRequest(id, p, true)
+	l = r.SubscriptionStatus()
+	assert.Equal(t, SubscriptionStateRequested, l[0].State)
+	assert.Equal(t, 0, len(l[0].Instances))
+	assert.Equal(t, "", l[0].LastError)
+
+	r.forget(id)
+	assert.Equal(t, 0, len(r.SubscriptionStatus()))
+}
+
+func TestSubscriptionStatusFailed(t *testing.T) {
+	r := &Subscriber{status: make(map[string]*SubscriptionStatus)}
+	id := "status-2"
+
+	r.recordRequest(id, GetSubscriptionparams(), false)
+	r.recordResponse(&apimodel.SubscriptionResponse{SubscriptionID: &id, SubscriptionInstances: []*apimodel.SubscriptionInstance{statusInstance(1, 0, "timeout")}})
+	assert.Equal(t, SubscriptionStateFailed, r.SubscriptionStatus()[0].State)
+}
+
+func TestSubscriptionStatusMergeDetails(t *testing.T) {
+	r := &Subscriber{status: make(map[string]*SubscriptionStatus)}
+	id := "status-3"
+
+	p := GetSubscriptionparams()
+	other := *p.SubscriptionDetails[0]
+	otherId := eventInstanceId + 1
+	other.XappEventInstanceID = &otherId
+	p.SubscriptionDetails = append(p.SubscriptionDetails, &other)
+	r.recordRequest(id, p, false)
+
+	// Subscribing some of the details again keeps the others
+	again := *p
+	again.SubscriptionDetails = p.SubscriptionDetails[1:]
+	r.recordRequest(id, &again, false)
+	assert.Equal(t, 2, len(r.SubscriptionStatus()[0].Params.SubscriptionDetails))
+	assert.Equal(t, 2, len(p.SubscriptionDetails))
+
+	// A modification replaces them
+	r.recordRequest(id, &again, true)
+	l := r.SubscriptionStatus()
+	assert.Equal(t, 1, len(l[0].Params.SubscriptionDetails))
+	assert.Equal(t, otherId, *l[0].Params.SubscriptionDetails[0].XappEventInstanceID)
+}
+
+func TestSubscriptionStatusURL(t *testing.T) {
+	Subscription.SetResponseCB(nil)
+	_, err := Subscription.Subscribe(GetSubscriptionparams())
+	assert.Equal(t, nil, err)
+
+	req, _ := http.NewRequest("GET", SubscriptionStatusURL, nil)
+	response := executeRequest(req, nil)
+	checkResponseCode(t, http.StatusOK, response.Code)
+
+	var l []SubscriptionStatus
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&l))
+	assert.NotEqual(t, 0, len(l))
+
+	baseDir := Resource.CollectDefaultSymptomData("", nil)
+	data, err := ioutil.ReadFile(baseDir + "subscriptions.json")
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), l[0].SubscriptionID)
+}
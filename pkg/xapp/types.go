@@ -35,6 +35,7 @@ type RMRClient struct {
 	readyCb           ReadyCB
 	readyCbParams     interface{}
 	maxRetryOnFailure int
+	log               *Log
 }
 
 type RMRMeid struct {
@@ -294,10 +294,17 @@ func init() {
 	if !viper.IsSet("controls.logger.noFormat") || !viper.GetBool("controls.logger.noFormat") {
 		Logger.SetFormat(0)
 	}
+	Logger.SetBufferSize(configLogBufferSize())
+	Logger.SetComponentLevels(configComponentLevels())
+	if err := Logger.SetOutputFormat(viper.GetString("controls.logger.format")); err != nil {
+		Logger.Error("Invalid controls.logger.format: %v", err)
+	}
 
 	Resource = NewRouter()
 	Config = Configurator{}
 	Metric = NewMetrics(viper.GetString("metrics.url"), viper.GetString("metrics.namespace"), Resource.router)
+	configStatus.registerMetrics()
+	Metric.RegisterMeasurements(configMeasurements())
 	Subscription = NewSubscriber(viper.GetString("controls.subscription.host"), viper.GetInt("controls.subscription.timeout"))
 	SdlStorage = NewSdlStorage()
 	Sdl = NewSDLClient(viper.GetString("controls.db.namespace"))
@@ -360,7 +367,14 @@ func RunWithRunParams(c MessageConsumer, params RunParams) {
 	if params.SdlCheck {
 		SdlStorage.TestConnection(viper.GetString("controls.db.namespace"))
 	}
+	configVersions.record(viper.ConfigFileUsed())
+	if viper.GetBool("controls.config.applyUpdates") {
+		if err := Config.EnableConfigUpdates(); err != nil {
+			Logger.Error("Enabling config updates failed: %v", err)
+		}
+	}
 	go registerXapp()
+	go Subscription.reconcileRegistry()
 
 	Rmr.Start(c)
 }